@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// agentAPIPath builds /api/investigations/:id/agents/:agent/<action>. The
+// server stores agent names in lower case.
+func agentAPIPath(investigationID int, agentName, action string) string {
+	return fmt.Sprintf("/api/investigations/%d/agents/%s/%s", investigationID, strings.ToLower(agentName), action)
+}
+
+// Stop a running agent through the server, which records it as failed so it
+// can be retried. The agent's PID belongs to the server's machine, so it is
+// never signalled from here.
+func stopAgentCmd(investigationID int, agentName string) tea.Cmd {
+	return func() tea.Msg {
+		path := agentAPIPath(investigationID, agentName, "stop")
+		if err := api.post(context.Background(), path, nil, nil); err != nil {
+			if endpointMissing(err) {
+				err = fmt.Errorf("server has no %s endpoint; update the UI server", path)
+			}
+			return agentStoppedMsg{investigationID: investigationID, agentName: agentName, err: err}
+		}
+		return agentStoppedMsg{investigationID: investigationID, agentName: agentName}
+	}
+}
+
+// Re-run a single agent, passing the reviewer's extra context along
+func retryAgentCmd(investigationID int, agentName, extraContext string) tea.Cmd {
+	return func() tea.Msg {
+		path := agentAPIPath(investigationID, agentName, "retry")
+		body := map[string]string{"context": extraContext}
+		if err := api.post(context.Background(), path, body, nil); err != nil {
+			if endpointMissing(err) {
+				err = fmt.Errorf("server has no %s endpoint; update the UI server", path)
+			}
+			return agentRetriedMsg{investigationID: investigationID, agentName: agentName, err: err}
+		}
+		return agentRetriedMsg{investigationID: investigationID, agentName: agentName}
+	}
+}
+
+// failedAgents lists the investigation's agents that ended in error
+func (m model) failedAgents(investigationID int) []string {
+	var names []string
+	for name, state := range m.agents[investigationID] {
+		if state.Status == "error" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// confirmStopAgent asks before stopping the agent on the active tab
+func (m *model) confirmStopAgent(inv *Investigation) tea.Cmd {
+	state := m.getAgentState(inv.ID, m.getActiveAgentName())
+	if state == nil || state.Status != "running" {
+		return m.notify(severityInfo, "stop agent", fmt.Sprintf("%s agent isn't running", m.getActiveAgentName()), nil)
+	}
+	m.agentActionTarget = state.Name
+	m.pushModal(modalConfirm)
+	m.confirmAction = "stop_agent"
+	m.confirmMessage = fmt.Sprintf("Stop the %s agent on #%d? It will be marked as failed and can be retried.", m.getActiveAgentName(), inv.ID)
+	return nil
+}
+
+// openAgentRetry opens the retry dialog for the given agents
+func (m *model) openAgentRetry(agents []string) tea.Cmd {
+	m.agentRetryTargets = agents
+	m.agentRetryPending = 0
+	m.agentRetryError = ""
+	m.agentRetryArea.SetValue("")
+	m.pushModal(modalAgentRetry)
+	return m.agentRetryArea.Focus()
+}
+
+// retryActiveAgent opens the retry dialog for the agent on the active tab
+func (m *model) retryActiveAgent(inv *Investigation) tea.Cmd {
+	state := m.getAgentState(inv.ID, m.getActiveAgentName())
+	switch {
+	case state == nil:
+		return m.notify(severityInfo, "retry agent", fmt.Sprintf("%s agent hasn't run yet", m.getActiveAgentName()), nil)
+	case state.Status == "running":
+		return m.notify(severityInfo, "retry agent", fmt.Sprintf("%s agent is still running; stop it first", m.getActiveAgentName()), nil)
+	}
+	return m.openAgentRetry([]string{state.Name})
+}
+
+// retryFailedAgents opens the retry dialog for every failed agent
+func (m *model) retryFailedAgents(inv *Investigation) tea.Cmd {
+	failed := m.failedAgents(inv.ID)
+	if len(failed) == 0 {
+		return m.notify(severityInfo, "retry agents", fmt.Sprintf("No failed agents on #%d", inv.ID), nil)
+	}
+	return m.openAgentRetry(failed)
+}
+
+func (m model) handleAgentRetryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.agentRetryPending > 0 {
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, keys.Escape):
+		m.agentRetryArea.Blur()
+		m.closeModal(modalAgentRetry)
+		return m, nil
+
+	case key.Matches(msg, keys.Save), key.Matches(msg, keys.Enter):
+		inv := m.getSelectedInvestigation()
+		if inv == nil {
+			return m, nil
+		}
+		extra := strings.TrimSpace(m.agentRetryArea.Value())
+		var cmds []tea.Cmd
+		for _, name := range m.agentRetryTargets {
+			cmds = append(cmds, retryAgentCmd(inv.ID, name, extra))
+		}
+		m.agentRetryPending = len(cmds)
+		m.agentRetryError = ""
+		return m, tea.Batch(cmds...)
+	}
+	var cmd tea.Cmd
+	m.agentRetryArea, cmd = m.agentRetryArea.Update(msg)
+	return m, cmd
+}
+
+func (m model) renderAgentRetryDialog() string {
+	inv := m.getSelectedInvestigation()
+	dialogWidth := m.modalWidth(70)
+	inner := dialogWidth - 8
+
+	title := "Retry agent"
+	if len(m.agentRetryTargets) > 1 {
+		title = fmt.Sprintf("Retry %d failed agents", len(m.agentRetryTargets))
+	}
+	header := lipgloss.NewStyle().Bold(true).Foreground(c1Primary).Render(withIcon(glyphReset, title))
+
+	var lines []string
+	for _, name := range m.agentRetryTargets {
+		line := lipgloss.NewStyle().Bold(true).Foreground(textPrimary).Render(name)
+		if inv != nil {
+			if state := m.getAgentState(inv.ID, name); state != nil && state.ErrorMessage != "" {
+				line += dimmedTextStyle.Render(" — " + truncateStr(firstLine(state.ErrorMessage), inner-len(name)-3))
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	m.agentRetryArea.SetWidth(inner)
+	label := lipgloss.NewStyle().Foreground(textSecondary).
+		Render("Extra context for the retry (optional), e.g. a channel to search or what went wrong:")
+
+	var errorLine string
+	if m.agentRetryError != "" {
+		errorLine = lipgloss.NewStyle().Foreground(statusError).Bold(true).Width(inner).Render("Error: " + m.agentRetryError)
+	}
+	footer := dimmedTextStyle.Render("Enter/Ctrl+S: retry • Esc: cancel")
+	if m.agentRetryPending > 0 {
+		footer = m.spinner.View() + " Starting..."
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		header, "",
+		strings.Join(lines, "\n"), "",
+		label,
+		m.agentRetryArea.View(), "",
+		errorLine,
+		footer,
+	)
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(c1Primary).
+		BorderBackground(bgPrimary).
+		Background(bgPrimary).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(content)
+}
+
+// renderAgentErrorDetails shows a failed agent's full error and how it
+// exited. It renders nothing for agents that didn't fail.
+func (m model) renderAgentErrorDetails(state *AgentState, width int) string {
+	if state.Status != "error" {
+		return ""
+	}
+	innerWidth := width - 8
+
+	header := lipgloss.NewStyle().Bold(true).Foreground(statusError).Render(withIcon(glyphFailed, "AGENT FAILED"))
+	message := state.ErrorMessage
+	if message == "" {
+		message = "No error message was recorded."
+	}
+	body := lipgloss.NewStyle().Foreground(textPrimary).Width(innerWidth).Render(message)
+
+	var exit []string
+	if !state.CompletedAt.IsZero() {
+		exit = append(exit, "exited "+state.CompletedAt.Format("15:04:05"))
+	}
+	if state.Runtime > 0 {
+		exit = append(exit, "after "+state.Runtime.Round(time.Second).String())
+	}
+	if state.PID > 0 {
+		exit = append(exit, fmt.Sprintf("PID %d", state.PID))
+	}
+	if state.RunNumber > 0 {
+		exit = append(exit, fmt.Sprintf("run #%d", state.RunNumber))
+	}
+	lines := []string{header, body}
+	if len(exit) > 0 {
+		lines = append(lines, dimmedTextStyle.Render(strings.Join(exit, " • ")))
+	}
+	return lipgloss.NewStyle().PaddingLeft(2).PaddingBottom(1).Render(strings.Join(lines, "\n"))
+}
+
+// agentControlHints lists the agent actions available for the given state
+func (m model) agentControlHints(inv *Investigation, state *AgentState) string {
+	var hints []string
+	switch state.Status {
+	case "running":
+		hints = append(hints, "[s] stop")
+	case "error", "completed":
+		hints = append(hints, "[t] retry with context")
+	}
+	if n := len(m.failedAgents(inv.ID)); n > 0 {
+		hints = append(hints, fmt.Sprintf("[T] retry all failed (%d)", n))
+	}
+	return strings.Join(hints, "  ")
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// apiClient wraps the Express API with per-request timeouts, bounded retries
+// for idempotent GETs and decoding of the server's {"error": ...} bodies.
+type apiClient struct {
+	base       string
+	http       *http.Client
+	timeout    time.Duration // Per attempt, unless the caller set a deadline
+	maxRetries int           // GET only
+	backoff    time.Duration // Doubled after each failed attempt
+}
+
+// api is the shared client used by every command in commands.go
+var api = newAPIClient(apiBase)
+
+func newAPIClient(base string) *apiClient {
+	return &apiClient{
+		base:       base,
+		http:       &http.Client{},
+		timeout:    10 * time.Second,
+		maxRetries: 2,
+		backoff:    250 * time.Millisecond,
+	}
+}
+
+// apiError is a non-2xx response. Message comes from the server's JSON body
+// when it sent one.
+type apiError struct {
+	Method  string
+	Path    string
+	Status  int
+	Message string
+}
+
+func (e *apiError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("HTTP %d: %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("HTTP %d", e.Status)
+}
+
+// retryable reports whether a GET should be attempted again
+func (e *apiError) retryable() bool {
+	return e.Status >= 500 || e.Status == http.StatusTooManyRequests
+}
+
+func (c *apiClient) get(ctx context.Context, path string, out interface{}) error {
+	var err error
+	delay := c.backoff
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		err = c.do(ctx, http.MethodGet, path, nil, out)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && !apiErr.retryable() {
+			return err
+		}
+	}
+	return err
+}
+
+// post and put are never retried: the server may have applied the change
+// before the connection failed.
+func (c *apiClient) post(ctx context.Context, path string, body, out interface{}) error {
+	return c.do(ctx, http.MethodPost, path, body, out)
+}
+
+func (c *apiClient) put(ctx context.Context, path string, body, out interface{}) error {
+	return c.do(ctx, http.MethodPut, path, body, out)
+}
+
+func (c *apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	// Outward actions wait on a server-side agent and bring their own deadline
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &apiError{
+			Method:  method,
+			Path:    path,
+			Status:  resp.StatusCode,
+			Message: decodeErrorBody(resp.Body),
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", path, err)
+	}
+	return nil
+}
+
+// decodeErrorBody extracts the message from the server's error JSON
+// ({"error": "...", "message": "..."}), falling back to the raw text.
+func decodeErrorBody(r io.Reader) string {
+	raw, err := io.ReadAll(io.LimitReader(r, 64*1024))
+	if err != nil || len(raw) == 0 {
+		return ""
+	}
+
+	var body struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(raw, &body) == nil && (body.Error != "" || body.Message != "") {
+		switch {
+		case body.Error == "":
+			return body.Message
+		case body.Message == "" || body.Message == body.Error:
+			return body.Error
+		default:
+			return body.Error + " — " + body.Message
+		}
+	}
+
+	text := strings.TrimSpace(string(raw))
+	if strings.HasPrefix(text, "<") {
+		return "" // HTML error page, nothing useful to show
+	}
+	return truncateStr(text, 200)
+}
+
+// isCanceled reports whether err came from a context we cancelled on purpose
+// (e.g. the user moved to another investigation); such results are dropped.
+func isCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// endpointMissing reports a 404 from Express's default handler, i.e. the
+// route doesn't exist, as opposed to a route reporting a missing record.
+func endpointMissing(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound && apiErr.Message == ""
+}
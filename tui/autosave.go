@@ -0,0 +1,412 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// autosaveInterval is how often in-progress drafts are written out
+const autosaveInterval = 5 * time.Second
+
+// Kinds of draft that are autosaved
+const (
+	draftResponse    = "response"     // Customer response textarea
+	draftKB          = "kb"           // KB article textarea
+	draftLinearDraft = "linear_draft" // Linear draft form, as markdown
+	draftCreate      = "create"       // New investigation form
+	draftReset       = "reset"        // Hard reset context
+	draftReply       = "reply"        // Customer reply context
+)
+
+// draftsDir is where drafts are autosaved; set from -state-dir in main
+var draftsDir = filepath.Join(defaultStateDir(), "drafts")
+
+// defaultStateDir follows XDG_STATE_HOME, falling back to ~/.local/state
+func defaultStateDir() string {
+	if dir := os.Getenv("TUI_STATE_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "support-triage")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".local", "state", "support-triage")
+	}
+	return filepath.Join(os.TempDir(), "support-triage")
+}
+
+// savedDraft is one autosaved draft, stored as drafts/<kind>-<id>.json
+type savedDraft struct {
+	Kind            string            `json:"kind"`
+	InvestigationID int               `json:"investigation_id,omitempty"` // 0 for the create form
+	Content         string            `json:"content"`
+	Fields          map[string]string `json:"fields,omitempty"` // Create form: ticket and skill
+	SavedAt         time.Time         `json:"saved_at"`
+}
+
+// key names the draft's file, e.g. response-8314
+func (d savedDraft) key() string {
+	if d.InvestigationID == 0 {
+		return d.Kind
+	}
+	return fmt.Sprintf("%s-%d", d.Kind, d.InvestigationID)
+}
+
+// describe says what the draft is for in the recovery dialog
+func (d savedDraft) describe() string {
+	var what string
+	switch d.Kind {
+	case draftResponse:
+		what = "customer response"
+	case draftKB:
+		what = "KB article"
+	case draftLinearDraft:
+		what = "Linear draft"
+	case draftCreate:
+		what = "new investigation"
+		if ticket := d.Fields["ticket"]; ticket != "" {
+			what += " for ticket " + ticket
+		}
+		return what
+	case draftReset:
+		what = "reset context"
+	case draftReply:
+		what = "reply context"
+	default:
+		what = d.Kind
+	}
+	return fmt.Sprintf("#%d %s", d.InvestigationID, what)
+}
+
+// currentDrafts collects every in-progress edit worth keeping. Editors
+// that were opened but not changed aren't drafts.
+func (m model) currentDrafts() []savedDraft {
+	var drafts []savedDraft
+	if inv := m.getSelectedInvestigation(); inv != nil {
+		id := inv.ID
+		if m.editingResponse {
+			content := m.responseTextarea.Value()
+			if resp := m.customerResponses[id]; resp == nil || resp.Content != content {
+				drafts = append(drafts, savedDraft{Kind: draftResponse, InvestigationID: id, Content: content})
+			}
+		}
+		if m.editingKB {
+			content := m.kbTextarea.Value()
+			if article := m.kbArticles[id]; article == nil || article.Content != content {
+				drafts = append(drafts, savedDraft{Kind: draftKB, InvestigationID: id, Content: content})
+			}
+		}
+		if m.isModalOpen(modalReset) {
+			if content := strings.TrimSpace(m.resetContextArea.Value()); content != "" {
+				drafts = append(drafts, savedDraft{Kind: draftReset, InvestigationID: id, Content: content})
+			}
+		}
+		if m.isModalOpen(modalReply) {
+			if content := strings.TrimSpace(m.replyContextArea.Value()); content != "" {
+				drafts = append(drafts, savedDraft{Kind: draftReply, InvestigationID: id, Content: content})
+			}
+		}
+	}
+	if m.draftDirty && m.draftFormFor != 0 {
+		drafts = append(drafts, savedDraft{Kind: draftLinearDraft, InvestigationID: m.draftFormFor, Content: m.formDraft().Markdown()})
+	}
+	if m.isModalOpen(modalCreate) {
+		ticket := strings.TrimSpace(m.createTicketInput.Value())
+		content := strings.TrimSpace(m.createContextArea.Value())
+		if ticket != "" || content != "" {
+			drafts = append(drafts, savedDraft{Kind: draftCreate, Content: content,
+				Fields: map[string]string{"ticket": ticket, "skill": skillOptions[m.createSkill]}})
+		}
+	}
+	return drafts
+}
+
+// autosavePlan works out which draft files to write and which to remove,
+// and records the result as written. Drafts still waiting to be recovered
+// are left alone so a new edit can't clobber them.
+func (m *model) autosavePlan(now time.Time) (write map[string][]byte, remove []string) {
+	pending := map[string]bool{}
+	for _, d := range m.recovered {
+		pending[d.key()] = true
+	}
+	current := map[string]bool{}
+	write = map[string][]byte{}
+	for _, d := range m.currentDrafts() {
+		k := d.key()
+		if pending[k] {
+			continue
+		}
+		current[k] = true
+		// Compare without the timestamp so unchanged drafts aren't rewritten
+		body, _ := json.Marshal(d)
+		if m.autosaved[k] == string(body) {
+			continue
+		}
+		m.autosaved[k] = string(body)
+		d.SavedAt = now
+		file, _ := json.MarshalIndent(d, "", "  ")
+		write[k] = file
+	}
+	for k := range m.autosaved {
+		if !current[k] {
+			remove = append(remove, k)
+			delete(m.autosaved, k)
+		}
+	}
+	return write, remove
+}
+
+// writeDrafts applies an autosave plan. Files are replaced by rename so a
+// crash mid-write can't leave half a draft.
+func writeDrafts(dir string, write map[string][]byte, remove []string) error {
+	if len(write) > 0 {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+	var errs []error
+	for k, body := range write {
+		path := filepath.Join(dir, k+".json")
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, body, 0600); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, k := range remove {
+		if err := os.Remove(filepath.Join(dir, k+".json")); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Write out the drafts in the background
+func autosaveCmd(dir string, write map[string][]byte, remove []string) tea.Cmd {
+	return func() tea.Msg {
+		return draftsAutosavedMsg{err: writeDrafts(dir, write, remove)}
+	}
+}
+
+// flushDrafts brings the drafts directory up to date before exiting, so
+// a clean quit right after saving doesn't offer to recover the save.
+func (m model) flushDrafts() error {
+	write, remove := m.autosavePlan(time.Now())
+	return writeDrafts(draftsDir, write, remove)
+}
+
+// Read drafts left behind by a previous session, oldest first. Unreadable
+// files are skipped rather than blocking startup.
+func loadRecoveredDraftsCmd(dir string) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			return draftsRecoveredMsg{}
+		}
+		if err != nil {
+			return draftsRecoveredMsg{err: err}
+		}
+		var drafts []savedDraft
+		for _, e := range entries {
+			if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+				continue
+			}
+			content, err := os.ReadFile(filepath.Join(dir, e.Name()))
+			if err != nil {
+				continue
+			}
+			var d savedDraft
+			if json.Unmarshal(content, &d) != nil || d.Kind == "" {
+				continue
+			}
+			drafts = append(drafts, d)
+		}
+		sort.Slice(drafts, func(i, j int) bool { return drafts[i].SavedAt.Before(drafts[j].SavedAt) })
+		return draftsRecoveredMsg{drafts: drafts}
+	}
+}
+
+// recoveryDue reports whether to offer the recovered drafts now: once the
+// investigations have loaded and nothing else is being edited.
+func (m model) recoveryDue() bool {
+	return len(m.recovered) > 0 && !m.recoveryDeferred && !m.loading &&
+		m.topModal() == modalNone && !m.editingResponse && !m.editingKB && !m.editingDraft && !m.selecting
+}
+
+// discardRecovered deletes the draft under the cursor
+func (m *model) discardRecovered() error {
+	d := m.recovered[m.recoveryCursor]
+	m.recovered = append(m.recovered[:m.recoveryCursor:m.recoveryCursor], m.recovered[m.recoveryCursor+1:]...)
+	if m.recoveryCursor >= len(m.recovered) && m.recoveryCursor > 0 {
+		m.recoveryCursor--
+	}
+	err := os.Remove(filepath.Join(draftsDir, d.key()+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	return err
+}
+
+// restoreRecovered reopens the draft under the cursor where it was being
+// written. From then on it is autosaved like any other edit.
+func (m *model) restoreRecovered() tea.Cmd {
+	d := m.recovered[m.recoveryCursor]
+	if d.InvestigationID != 0 {
+		index := -1
+		for i, inv := range m.investigations {
+			if inv.ID == d.InvestigationID {
+				index = i
+			}
+		}
+		if index < 0 {
+			return m.notify(severityWarning, "recover draft",
+				fmt.Sprintf("#%d is no longer in the investigation list; discard the draft or try again after a refresh", d.InvestigationID), nil)
+		}
+		m.selectedIndex = index
+		m.cp1Loaded = 0
+		m.kbScroll = 0
+	}
+	m.recovered = append(m.recovered[:m.recoveryCursor:m.recoveryCursor], m.recovered[m.recoveryCursor+1:]...)
+	m.recoveryCursor = 0
+	m.closeModal(modalRecovery)
+	// The file is rewritten by the next autosave, or removed once the edit
+	// is saved or abandoned
+	m.autosaved[d.key()] = ""
+
+	var cmds []tea.Cmd
+	switch d.Kind {
+	case draftResponse:
+		m.activeTab = TabSummary
+		cmds = append(cmds, m.selectInvestigation(), m.summaryCmd(d.InvestigationID), m.customerResponseCmd(d.InvestigationID))
+		m.editingResponse = true
+		m.beginEdit(docKey{d.InvestigationID, "customer-response.md"})
+		m.responseTextarea.SetValue(d.Content)
+		m.lintEditedResponse()
+		cmds = append(cmds, m.responseTextarea.Focus())
+	case draftKB:
+		m.activeTab = TabKB
+		cmds = append(cmds, m.selectInvestigation())
+		m.editingKB = true
+		m.beginEdit(docKey{d.InvestigationID, kbArticleFile})
+		m.kbTextarea.SetValue(d.Content)
+		cmds = append(cmds, m.kbTextarea.Focus())
+	case draftLinearDraft:
+		m.activeTab = TabLinearDraft
+		cmds = append(cmds, m.selectInvestigation())
+		m.fillDraftForm(d.InvestigationID, parseLinearDraft(d.Content))
+		m.draftDirty = true
+		m.startDraftEdit()
+	case draftReset:
+		cmds = append(cmds, m.selectInvestigation())
+		m.pushModal(modalReset)
+		m.resetContextArea.SetValue(d.Content)
+		m.resetError = ""
+		m.resettingInProgress = false
+		cmds = append(cmds, m.resetContextArea.Focus())
+	case draftReply:
+		cmds = append(cmds, m.selectInvestigation())
+		m.pushModal(modalReply)
+		m.replyContextArea.SetValue(d.Content)
+		m.replyError = ""
+		m.approvingReply = false
+		cmds = append(cmds, m.replyContextArea.Focus())
+	case draftCreate:
+		m.pushModal(modalCreate)
+		m.createFocusField = 0
+		m.createTicketInput.SetValue(d.Fields["ticket"])
+		m.createContextArea.SetValue(d.Content)
+		m.createSkill = 0
+		for i, skill := range skillOptions {
+			if skill == d.Fields["skill"] {
+				m.createSkill = i
+			}
+		}
+		m.createError = ""
+		m.creatingInProgress = false
+		cmds = append(cmds, m.createTicketInput.Focus())
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m model) handleRecoveryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Up):
+		if m.recoveryCursor > 0 {
+			m.recoveryCursor--
+		}
+		return m, nil
+	case key.Matches(msg, keys.Down):
+		if m.recoveryCursor < len(m.recovered)-1 {
+			m.recoveryCursor++
+		}
+		return m, nil
+	case key.Matches(msg, keys.Enter), msg.String() == "r":
+		return m, m.restoreRecovered()
+	case msg.String() == "d":
+		err := m.discardRecovered()
+		if len(m.recovered) == 0 {
+			m.closeModal(modalRecovery)
+		}
+		if err != nil {
+			return m, m.notify(severityError, "discard draft", err.Error(), nil)
+		}
+		return m, nil
+	case key.Matches(msg, keys.Escape):
+		// Keep the files; they're offered again next launch
+		m.recoveryDeferred = true
+		m.closeModal(modalRecovery)
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m model) renderRecoveryDialog() string {
+	dialogWidth := m.modalWidth(80)
+	inner := dialogWidth - 8
+
+	header := lipgloss.NewStyle().Bold(true).Foreground(c1Primary).
+		Render(withIcon(glyphSave, fmt.Sprintf("Recovered %d unsaved draft(s)", len(m.recovered))))
+	intro := lipgloss.NewStyle().Foreground(textSecondary).Width(inner).
+		Render("These were being edited when the TUI last exited. Restore one to pick up where you left off.")
+
+	var lines []string
+	for i, d := range m.recovered {
+		line := fmt.Sprintf("%-36s %s", truncateStr(d.describe(), 36), d.SavedAt.Local().Format("Jan 2 15:04"))
+		preview := dimmedTextStyle.Render("    " + truncateStr(firstLine(strings.TrimSpace(d.Content)), inner-4))
+		if i == m.recoveryCursor {
+			line = lipgloss.NewStyle().Bold(true).Foreground(c1Primary).Render("▸ " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line, preview)
+	}
+
+	footer := dimmedTextStyle.Render("↑↓: select • Enter/r: restore • d: discard • Esc: decide later")
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		header, "",
+		intro, "",
+		strings.Join(lines, "\n"), "",
+		footer,
+	)
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(c1Primary).
+		BorderBackground(bgPrimary).
+		Background(bgPrimary).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(content)
+}
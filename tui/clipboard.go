@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clipboardTool is a local clipboard program that reads the text on stdin
+type clipboardTool struct {
+	name string
+	args []string
+}
+
+// osc52Method names the escape-sequence fallback in toasts
+const osc52Method = "OSC 52"
+
+// osc52Limit is roughly where terminals start silently dropping OSC 52
+// payloads (xterm's default is 100000 base64 bytes).
+const osc52Limit = 74000
+
+// clipboardOutput is where OSC 52 sequences go; the terminal bubbletea draws to
+var clipboardOutput io.Writer = os.Stdout
+
+// clipboardTools lists the local programs worth trying, most specific first.
+// Over SSH none are tried: they would fill the remote machine's clipboard,
+// not the user's.
+func clipboardTools() []clipboardTool {
+	if os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != "" {
+		return nil
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return []clipboardTool{{name: "pbcopy"}}
+	case "windows":
+		return []clipboardTool{{name: "clip"}}
+	}
+
+	var tools []clipboardTool
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		tools = append(tools, clipboardTool{name: "wl-copy"})
+	}
+	if os.Getenv("DISPLAY") != "" {
+		tools = append(tools,
+			clipboardTool{name: "xclip", args: []string{"-selection", "clipboard"}},
+			clipboardTool{name: "xsel", args: []string{"--clipboard", "--input"}},
+		)
+	}
+	// WSL exposes the Windows clipboard through clip.exe
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		tools = append(tools, clipboardTool{name: "clip.exe"})
+	}
+	return tools
+}
+
+// writeClipboard copies text with the first tool that works, falling back to
+// OSC 52. It returns the method used. OSC 52 can't be confirmed, so callers
+// should word their feedback accordingly.
+func writeClipboard(text string) (string, error) {
+	var failures []string
+	for _, tool := range clipboardTools() {
+		path, err := exec.LookPath(tool.name)
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, tool.args...)
+		cmd.Stdin = strings.NewReader(text)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			msg := strings.TrimSpace(stderr.String())
+			if msg == "" {
+				msg = err.Error()
+			}
+			failures = append(failures, tool.name+": "+msg)
+			continue
+		}
+		return tool.name, nil
+	}
+
+	if err := writeOSC52(clipboardOutput, text); err != nil {
+		failures = append(failures, osc52Method+": "+err.Error())
+		return "", errors.New(strings.Join(failures, "; "))
+	}
+	return osc52Method, nil
+}
+
+// writeOSC52 asks the terminal to set the system clipboard. Inside tmux and
+// screen the sequence is wrapped so it reaches the outer terminal (tmux also
+// needs `set -g allow-passthrough on` or `set-clipboard on`).
+func writeOSC52(w io.Writer, text string) error {
+	encoded := base64.StdEncoding.EncodeToString([]byte(text))
+	if len(encoded) > osc52Limit {
+		return fmt.Errorf("%d bytes is too large for the terminal clipboard", len(text))
+	}
+
+	_, err := io.WriteString(w, terminalPassthrough("\x1b]52;c;"+encoded+"\x07"))
+	return err
+}
+
+// terminalPassthrough wraps an OSC sequence so tmux and screen forward it
+// to the outer terminal instead of swallowing it.
+func terminalPassthrough(seq string) string {
+	switch {
+	case os.Getenv("TMUX") != "":
+		return "\x1bPtmux;\x1b" + seq + "\x1b\\"
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		return "\x1bP" + seq + "\x1b\\"
+	}
+	return seq
+}
+
+// Copy a customer response to the clipboard
+func copyToClipboardCmd(investigationID int, content string) tea.Cmd {
+	return func() tea.Msg {
+		method, err := writeClipboard(content)
+		if err != nil {
+			return errMsg{err: fmt.Errorf("copy failed: %w", err), source: "copy to clipboard", retry: copyToClipboardCmd(investigationID, content)}
+		}
+		return responseCopiedMsg{investigationID: investigationID, method: method}
+	}
+}
+
+// Copy arbitrary text; what describes it in the toast
+func copyTextCmd(what, text string) tea.Cmd {
+	return func() tea.Msg {
+		method, err := writeClipboard(text)
+		if err != nil {
+			return errMsg{err: fmt.Errorf("copy failed: %w", err), source: "copy " + what, retry: copyTextCmd(what, text)}
+		}
+		return textCopiedMsg{what: what, method: method}
+	}
+}
+
+// copiedNotice words the toast for a finished copy. OSC 52 has no
+// acknowledgement, so it only claims the text was sent.
+func copiedNotice(what, method string) string {
+	if method == osc52Method {
+		return "Sent " + what + " to the terminal clipboard via OSC 52 (needs terminal support)"
+	}
+	return "Copied " + what + " via " + method
+}
@@ -3,12 +3,12 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
 	"os"
 	"os/exec"
-	"strconv"
 	"strings"
 	"time"
 
@@ -37,19 +37,30 @@ func resolveInvestigationFile(investigationID int, filename string) string {
 	return "" // Not found
 }
 
+// listTimeout bounds `triage list`; the tick reissues it every 2s, so a hung
+// CLI must not be left running
+const listTimeout = 10 * time.Second
+
 // Load all investigations from CLI
-func loadInvestigationsCmd() tea.Cmd {
+func loadInvestigationsCmd(gen int) tea.Cmd {
 	return func() tea.Msg {
-	cmd := exec.Command(cliPath, "list", "--json")
+	ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, cliPath, "list", "--json")
 	output, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("triage list timed out after %s", listTimeout)
+	}
 	if err != nil {
-		return errMsg{err}
+		// A missing CLI binary can't be fixed by retrying
+		fatal := errors.Is(err, exec.ErrNotFound) || errors.Is(err, os.ErrNotExist)
+		return errMsg{err: err, source: "load investigations", retry: reloadCmd(loadInvestigations, 0, ""), fatal: fatal, poll: true}
 	}
 
 	var investigations []Investigation
 	err = json.Unmarshal(output, &investigations)
 	if err != nil {
-		return errMsg{err}
+		return errMsg{err: err, source: "load investigations", retry: reloadCmd(loadInvestigations, 0, ""), poll: true}
 	}
 
 		// Initialize AgentStatuses map for each investigation
@@ -57,94 +68,109 @@ func loadInvestigationsCmd() tea.Cmd {
 			investigations[i].AgentStatuses = make(map[string]string)
 		}
 
-		return investigationsLoadedMsg{investigations: investigations}
+		return investigationsLoadedMsg{investigations: investigations, gen: gen}
 	}
 }
 
-// Load agent statuses for a specific investigation via Express API
-func loadAgentStatusesCmd(investigationID int) tea.Cmd {
+// Load agent statuses for a specific investigation via Express API.
+// ctx is cancelled when the selection moves to another investigation.
+func loadAgentStatusesCmd(ctx context.Context, investigationID, gen int) tea.Cmd {
 	return func() tea.Msg {
-		url := fmt.Sprintf("%s/api/investigations/%d/agents", apiBase, investigationID)
-		resp, err := http.Get(url)
+		agents, err := fetchAgentStates(ctx, investigationID)
 		if err != nil {
-			return errMsg{fmt.Errorf("API error loading agents: %w", err)}
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != 200 {
-			return errMsg{fmt.Errorf("agents API returned HTTP %d", resp.StatusCode)}
-		}
-
-		var apiAgents []struct {
-			ID              int    `json:"id"`
-			InvestigationID int    `json:"investigation_id"`
-			RunNumber       int    `json:"run_number"`
-			AgentName       string `json:"agent_name"`
-			PID             int    `json:"pid"`
-			Status          string `json:"status"`
-			StartedAt       string `json:"started_at"`
-			CompletedAt     string `json:"completed_at"`
-			ErrorMessage    string `json:"error_message"`
-			FindingsFile    string `json:"findings_file"`
-		}
-
-		decoder := json.NewDecoder(resp.Body)
-		if err := decoder.Decode(&apiAgents); err != nil {
-			return errMsg{fmt.Errorf("failed to parse agents response: %w", err)}
-		}
-
-		// Convert to AgentState map
-		agents := make(map[string]*AgentState)
-		for _, agent := range apiAgents {
-			startedAt, _ := time.Parse(time.RFC3339, agent.StartedAt)
-			runtime := time.Duration(0)
-			if !startedAt.IsZero() {
-				if agent.CompletedAt != "" {
-					completedAt, _ := time.Parse(time.RFC3339, agent.CompletedAt)
-					if !completedAt.IsZero() {
-						runtime = completedAt.Sub(startedAt)
-					}
-				} else {
-					runtime = time.Since(startedAt)
-				}
+			if isCanceled(err) {
+				return nil
 			}
-
-			agents[agent.AgentName] = &AgentState{
-				Name:         agent.AgentName,
-				Status:       agent.Status,
-				PID:          agent.PID,
-				StartedAt:    startedAt,
-				Runtime:      runtime,
-				Logs:         []LogEntry{},
-				Findings:     []Finding{},
-				FindingsFile: agent.FindingsFile,
+			return errMsg{
+				err:    fmt.Errorf("API error loading agents: %w", err),
+				source: "load agents",
+				retry:  reloadCmd(loadAgentStatuses, investigationID, ""),
+				poll:   true,
 			}
 		}
 
 		return agentStatusesLoadedMsg{
 			investigationID: investigationID,
+			gen:             gen,
 			agents:          agents,
 		}
 	}
 }
 
-// Stream agent logs from activity-log.jsonl filtered by phase1-{agent} tag
-func streamAgentLogsCmd(investigationID int, agentName string) tea.Cmd {
+// fetchAgentStates loads an investigation's agents keyed by agent name
+func fetchAgentStates(ctx context.Context, investigationID int) (map[string]*AgentState, error) {
+	var apiAgents []struct {
+		ID              int    `json:"id"`
+		InvestigationID int    `json:"investigation_id"`
+		RunNumber       int    `json:"run_number"`
+		AgentName       string `json:"agent_name"`
+		PID             int    `json:"pid"`
+		Status          string `json:"status"`
+		StartedAt       string `json:"started_at"`
+		CompletedAt     string `json:"completed_at"`
+		ErrorMessage    string `json:"error_message"`
+		FindingsFile    string `json:"findings_file"`
+	}
+
+	path := fmt.Sprintf("/api/investigations/%d/agents", investigationID)
+	if err := api.get(ctx, path, &apiAgents); err != nil {
+		return nil, err
+	}
+
+	// Convert to AgentState map
+	agents := make(map[string]*AgentState)
+	for _, agent := range apiAgents {
+		startedAt, _ := time.Parse(time.RFC3339, agent.StartedAt)
+		completedAt, _ := time.Parse(time.RFC3339, agent.CompletedAt)
+		runtime := time.Duration(0)
+		if !startedAt.IsZero() {
+			if !completedAt.IsZero() {
+				runtime = completedAt.Sub(startedAt)
+			} else if agent.CompletedAt == "" {
+				runtime = time.Since(startedAt)
+			}
+		}
+
+		agents[agent.AgentName] = &AgentState{
+			Name:         agent.AgentName,
+			Status:       agent.Status,
+			PID:          agent.PID,
+			StartedAt:    startedAt,
+			CompletedAt:  completedAt,
+			Runtime:      runtime,
+			RunNumber:    agent.RunNumber,
+			ErrorMessage: agent.ErrorMessage,
+			Logs:         []LogEntry{},
+			Findings:     []Finding{},
+			FindingsFile: agent.FindingsFile,
+		}
+	}
+	return agents, nil
+}
+
+// Stream agent logs from activity-log.jsonl filtered by phase1-{agent} tag.
+// Like the other file loads below, nothing is returned once ctx is cancelled.
+func streamAgentLogsCmd(ctx context.Context, investigationID int, agentName string, gen int) tea.Cmd {
 	return func() tea.Msg {
 		// Read from activity-log.jsonl and filter by phase tag
 		logPath := resolveInvestigationFile(investigationID, "activity-log.jsonl")
 		if logPath == "" {
 			return agentLogsLoadedMsg{
 				investigationID: investigationID,
+				gen:             gen,
 				agentName:       agentName,
 				logs:            []LogEntry{},
 			}
 		}
 
 		content, err := os.ReadFile(logPath)
+		if ctx.Err() != nil {
+			return nil // The selection moved on while this was reading
+		}
 		if err != nil {
 			return agentLogsLoadedMsg{
 				investigationID: investigationID,
+				gen:             gen,
 				agentName:       agentName,
 				logs:            []LogEntry{},
 			}
@@ -184,6 +210,7 @@ func streamAgentLogsCmd(investigationID int, agentName string) tea.Cmd {
 
 		return agentLogsLoadedMsg{
 			investigationID: investigationID,
+			gen:             gen,
 			agentName:       agentName,
 			logs:            logs,
 		}
@@ -191,21 +218,25 @@ func streamAgentLogsCmd(investigationID int, agentName string) tea.Cmd {
 }
 
 // Load findings from markdown file
-func loadAgentFindingsCmd(investigationID int, agentName string) tea.Cmd {
+func loadAgentFindingsCmd(ctx context.Context, investigationID int, agentName string, gen int) tea.Cmd {
 	return func() tea.Msg {
 		findingsFile := fmt.Sprintf("%s-findings.md", strings.ToLower(agentName))
 		findingsPath := resolveInvestigationFile(investigationID, findingsFile)
 		if findingsPath == "" {
 			return agentFindingsLoadedMsg{
 				investigationID: investigationID,
+				gen:             gen,
 				agentName:       agentName,
 				findings:        []Finding{},
 			}
 		}
 
 		content, err := os.ReadFile(findingsPath)
+		if ctx.Err() != nil {
+			return nil // The selection moved on while this was reading
+		}
 		if err != nil {
-			return errMsg{err}
+			return errMsg{err: err, source: "load findings", retry: reloadCmd(loadAgentFindings, investigationID, agentName), poll: true}
 		}
 
 		// Simple markdown parsing - extract ## headers as findings
@@ -213,6 +244,7 @@ func loadAgentFindingsCmd(investigationID int, agentName string) tea.Cmd {
 
 		return agentFindingsLoadedMsg{
 			investigationID: investigationID,
+			gen:             gen,
 			agentName:       agentName,
 			findings:        findings,
 		}
@@ -253,71 +285,62 @@ func parseMarkdownFindings(content string) []Finding {
 }
 
 // Load ticket data from ticket-data.json
-func loadTicketDataCmd(investigationID int) tea.Cmd {
+func loadTicketDataCmd(ctx context.Context, investigationID, gen int) tea.Cmd {
 	return func() tea.Msg {
 		tdPath := resolveInvestigationFile(investigationID, "ticket-data.json")
 		if tdPath == "" {
 			return ticketDataLoadedMsg{
 				investigationID: investigationID,
+				gen:             gen,
 				data:            nil,
 			}
 		}
 
 		content, err := os.ReadFile(tdPath)
+		if ctx.Err() != nil {
+			return nil // The selection moved on while this was reading
+		}
 		if err != nil {
-			return errMsg{err}
+			return errMsg{err: err, source: "load ticket data", retry: reloadCmd(loadTicketData, investigationID, ""), poll: true}
 		}
 
 		var td TicketData
 		if err := json.Unmarshal(content, &td); err != nil {
-			return errMsg{fmt.Errorf("failed to parse ticket-data.json: %w", err)}
+			return errMsg{err: fmt.Errorf("failed to parse ticket-data.json: %w", err), source: "load ticket data", retry: reloadCmd(loadTicketData, investigationID, ""), poll: true}
 		}
 
 		return ticketDataLoadedMsg{
 			investigationID: investigationID,
+			gen:             gen,
 			data:            &td,
 		}
 	}
 }
 
 // Load combined phase1 findings
-func loadPhase1FindingsCmd(investigationID int) tea.Cmd {
+func loadPhase1FindingsCmd(ctx context.Context, investigationID, gen int) tea.Cmd {
 	return func() tea.Msg {
 		path := resolveInvestigationFile(investigationID, "phase1-findings.md")
 		if path == "" {
-			return phase1FindingsLoadedMsg{investigationID: investigationID, content: ""}
+			return phase1FindingsLoadedMsg{investigationID: investigationID, gen: gen, content: ""}
 		}
 		content, err := os.ReadFile(path)
+		if ctx.Err() != nil {
+			return nil // The selection moved on while this was reading
+		}
 		if err != nil {
-			return phase1FindingsLoadedMsg{investigationID: investigationID, content: ""}
+			return phase1FindingsLoadedMsg{investigationID: investigationID, gen: gen, content: ""}
 		}
-		return phase1FindingsLoadedMsg{investigationID: investigationID, content: string(content)}
+		return phase1FindingsLoadedMsg{investigationID: investigationID, gen: gen, content: string(content)}
 	}
 }
 
 // Update investigation fields via Express API
 func updateInvestigationCmd(investigationID int, fields map[string]string) tea.Cmd {
 	return func() tea.Msg {
-		body, err := json.Marshal(fields)
-		if err != nil {
-			return investigationUpdatedMsg{investigationID: investigationID, err: err}
-		}
-
-		url := fmt.Sprintf("%s/api/investigations/%d", apiBase, investigationID)
-		req, err := http.NewRequest("PUT", url, bytes.NewReader(body))
-		if err != nil {
-			return investigationUpdatedMsg{investigationID: investigationID, err: err}
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return investigationUpdatedMsg{investigationID: investigationID, err: fmt.Errorf("API error: %w", err)}
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != 200 {
-			return investigationUpdatedMsg{investigationID: investigationID, err: fmt.Errorf("update failed: HTTP %d", resp.StatusCode)}
+		path := fmt.Sprintf("/api/investigations/%d", investigationID)
+		if err := api.put(context.Background(), path, fields, nil); err != nil {
+			return investigationUpdatedMsg{investigationID: investigationID, err: fmt.Errorf("update failed: %w", err)}
 		}
 
 		return investigationUpdatedMsg{investigationID: investigationID, err: nil}
@@ -327,17 +350,11 @@ func updateInvestigationCmd(investigationID int, fields map[string]string) tea.C
 // Approve checkpoint via Express API
 func approveCheckpointCmd(investigationID int, checkpoint string) tea.Cmd {
 	return func() tea.Msg {
-		body := fmt.Sprintf(`{"action":"confirm","checkpoint":"%s"}`, checkpoint)
-		url := fmt.Sprintf("%s/api/investigations/%d/checkpoint", apiBase, investigationID)
-
-		resp, err := http.Post(url, "application/json", strings.NewReader(body))
-		if err != nil {
-			return errMsg{fmt.Errorf("API error: %w", err)}
-		}
-		defer resp.Body.Close()
+		body := map[string]string{"action": "confirm", "checkpoint": checkpoint}
+		path := fmt.Sprintf("/api/investigations/%d/checkpoint", investigationID)
 
-		if resp.StatusCode != 200 {
-			return errMsg{fmt.Errorf("checkpoint approval failed: HTTP %d", resp.StatusCode)}
+		if err := api.post(context.Background(), path, body, nil); err != nil {
+			return errMsg{err: fmt.Errorf("checkpoint approval failed: %w", err), source: "approve checkpoint", retry: approveCheckpointCmd(investigationID, checkpoint)}
 		}
 
 		return checkpointApprovedMsg{investigationID: investigationID}
@@ -345,19 +362,23 @@ func approveCheckpointCmd(investigationID int, checkpoint string) tea.Cmd {
 }
 
 // Load investigation summary
-func loadSummaryCmd(investigationID int) tea.Cmd {
+func loadSummaryCmd(ctx context.Context, investigationID, gen int) tea.Cmd {
 	return func() tea.Msg {
 		summaryPath := resolveInvestigationFile(investigationID, "summary.md")
 		if summaryPath == "" {
 			return summaryLoadedMsg{
 				investigationID: investigationID,
+				gen:             gen,
 				summary:         nil,
 			}
 		}
 
 		content, err := os.ReadFile(summaryPath)
+		if ctx.Err() != nil {
+			return nil // The selection moved on while this was reading
+		}
 		if err != nil {
-			return errMsg{err}
+			return errMsg{err: err, source: "load summary", retry: reloadCmd(loadSummary, investigationID, ""), poll: true}
 		}
 
 		// Parse the summary markdown
@@ -366,35 +387,43 @@ func loadSummaryCmd(investigationID int) tea.Cmd {
 
 		return summaryLoadedMsg{
 			investigationID: investigationID,
+			gen:             gen,
 			summary:         summary,
 		}
 	}
 }
 
 // Load customer response
-func loadCustomerResponseCmd(investigationID int) tea.Cmd {
+func loadCustomerResponseCmd(investigationID, gen int) tea.Cmd {
 	return func() tea.Msg {
 		responsePath := resolveInvestigationFile(investigationID, "customer-response.md")
 		if responsePath == "" {
 			return customerResponseLoadedMsg{
 				investigationID: investigationID,
+				gen:             gen,
 				response:        nil,
 			}
 		}
 
-		content, err := os.ReadFile(responsePath)
+		disk, err := readDiskState(responsePath)
 		if err != nil {
-			return errMsg{err}
+			return errMsg{err: err, source: "load customer response", retry: reloadCmd(loadCustomerResponse, investigationID, "")}
 		}
 
 		response := &CustomerResponse{
-			Content:  string(content),
+			Content:  disk.Content,
 			LastEdited: time.Now(),
 		}
+		if receipt := lastPublishReceipt(investigationID); receipt != nil {
+			response.Posted = receipt
+			response.PostedToPylon = receipt.Publisher != "dry-run"
+		}
 
 		return customerResponseLoadedMsg{
 			investigationID: investigationID,
+			gen:             gen,
 			response:        response,
+			disk:            disk,
 		}
 	}
 }
@@ -459,8 +488,10 @@ func parseSummaryMarkdown(content string) *InvestigationSummary {
 	return summary
 }
 
-// Save edited customer response
-func saveCustomerResponseCmd(investigationID int, content string) tea.Cmd {
+// Save edited customer response. expected is the file as it was when
+// editing started; if it has changed since, nothing is written and the
+// conflict is returned instead (see conflict.go).
+func saveCustomerResponseCmd(investigationID int, content string, expected *diskState) tea.Cmd {
 	return func() tea.Msg {
 		responsePath := resolveInvestigationFile(investigationID, "customer-response.md")
 		if responsePath == "" {
@@ -468,55 +499,19 @@ func saveCustomerResponseCmd(investigationID int, content string) tea.Cmd {
 			responsePath = fmt.Sprintf("%s/%d/customer-response.md", investigationsDir, investigationID)
 		}
 
-		err := os.WriteFile(responsePath, []byte(content), 0644)
+		disk, conflict, err := writeChecked(responsePath, content, expected)
 		if err != nil {
-			return errMsg{err}
+			return errMsg{err: err, source: "save customer response", retry: saveCustomerResponseCmd(investigationID, content, expected)}
 		}
-
-		return responseSavedMsg{investigationID: investigationID}
-	}
-}
-
-// Post customer response to Pylon via MCP
-func postToPylonCmd(investigationID int, content string) tea.Cmd {
-	return func() tea.Msg {
-		// Use Pylon MCP to post the response
-		// First, we need to get the ticket ID from the investigation
-		cmd := exec.Command(cliPath, "status", strconv.Itoa(investigationID), "--json")
-		output, err := cmd.Output()
-		if err != nil {
-			return errMsg{fmt.Errorf("failed to get investigation status: %w", err)}
-		}
-
-		var status struct {
-			Investigation struct {
-				ID int `json:"id"`
-			} `json:"investigation"`
-		}
-		err = json.Unmarshal(output, &status)
-		if err != nil {
-			return errMsg{fmt.Errorf("failed to parse investigation status: %w", err)}
+		if conflict != nil {
+			conflict.key = docKey{investigationID, "customer-response.md"}
+			conflict.save = func(expected *diskState) tea.Cmd {
+				return saveCustomerResponseCmd(investigationID, content, expected)
+			}
+			return saveConflictMsg{conflict: conflict}
 		}
 
-		// Use the pylon MCP tool to post a message
-		// For now, just mark as posted (actual MCP integration would go here)
-		// TODO: Implement actual Pylon MCP call:
-		// mcp__pylon__pylon_update_issue with the response content
-
-		return responsePostedMsg{investigationID: investigationID}
-	}
-}
-
-// Copy to clipboard (uses pbcopy on macOS)
-func copyToClipboardCmd(content string) tea.Cmd {
-	return func() tea.Msg {
-		cmd := exec.Command("pbcopy")
-		cmd.Stdin = strings.NewReader(content)
-		err := cmd.Run()
-		if err != nil {
-			return errMsg{err}
-		}
-		return responseCopiedMsg{}
+		return responseSavedMsg{investigationID: investigationID, content: content, disk: disk}
 	}
 }
 
@@ -541,24 +536,15 @@ func createInvestigationCmd(ticketID, skill, context string) tea.Cmd {
 // Hard reset an investigation with optional context
 func hardResetCmd(investigationID int, triggerSummary string) tea.Cmd {
 	return func() tea.Msg {
-		body, _ := json.Marshal(map[string]string{
-			"trigger_summary": triggerSummary,
-		})
-		url := fmt.Sprintf("%s/api/investigations/%d/hard-reset", apiBase, investigationID)
-		resp, err := http.Post(url, "application/json", bytes.NewReader(body))
-		if err != nil {
-			return hardResetCompletedMsg{investigationID: investigationID, err: err}
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != 200 {
-			return hardResetCompletedMsg{investigationID: investigationID, err: fmt.Errorf("HTTP %d", resp.StatusCode)}
-		}
+		body := map[string]string{"trigger_summary": triggerSummary}
+		path := fmt.Sprintf("/api/investigations/%d/hard-reset", investigationID)
 
 		var result struct {
 			NewRunNumber int `json:"newRunNumber"`
 		}
-		json.NewDecoder(resp.Body).Decode(&result)
+		if err := api.post(context.Background(), path, body, &result); err != nil {
+			return hardResetCompletedMsg{investigationID: investigationID, err: err}
+		}
 		return hardResetCompletedMsg{investigationID: investigationID, newRunNumber: result.NewRunNumber}
 	}
 }
@@ -566,24 +552,15 @@ func hardResetCmd(investigationID int, triggerSummary string) tea.Cmd {
 // Approve a new investigation run after customer reply detection
 func approveNewRunCmd(investigationID int, triggerSummary string) tea.Cmd {
 	return func() tea.Msg {
-		body, _ := json.Marshal(map[string]string{
-			"trigger_summary": triggerSummary,
-		})
-		url := fmt.Sprintf("%s/api/investigations/%d/approve-new-run", apiBase, investigationID)
-		resp, err := http.Post(url, "application/json", bytes.NewReader(body))
-		if err != nil {
-			return newRunApprovedMsg{investigationID: investigationID, err: err}
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != 200 {
-			return newRunApprovedMsg{investigationID: investigationID, err: fmt.Errorf("HTTP %d", resp.StatusCode)}
-		}
+		body := map[string]string{"trigger_summary": triggerSummary}
+		path := fmt.Sprintf("/api/investigations/%d/approve-new-run", investigationID)
 
 		var result struct {
 			NewRunNumber int `json:"newRunNumber"`
 		}
-		json.NewDecoder(resp.Body).Decode(&result)
+		if err := api.post(context.Background(), path, body, &result); err != nil {
+			return newRunApprovedMsg{investigationID: investigationID, err: err}
+		}
 		return newRunApprovedMsg{investigationID: investigationID, newRunNumber: result.NewRunNumber}
 	}
 }
@@ -591,12 +568,10 @@ func approveNewRunCmd(investigationID int, triggerSummary string) tea.Cmd {
 // Dismiss a customer reply notification without creating a new run
 func dismissReplyCmd(investigationID int) tea.Cmd {
 	return func() tea.Msg {
-		url := fmt.Sprintf("%s/api/investigations/%d/dismiss-reply", apiBase, investigationID)
-		resp, err := http.Post(url, "application/json", strings.NewReader("{}"))
-		if err != nil {
+		path := fmt.Sprintf("/api/investigations/%d/dismiss-reply", investigationID)
+		if err := api.post(context.Background(), path, struct{}{}, nil); err != nil {
 			return replyDismissedMsg{investigationID: investigationID, err: err}
 		}
-		defer resp.Body.Close()
 		return replyDismissedMsg{investigationID: investigationID}
 	}
 }
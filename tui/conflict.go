@@ -0,0 +1,272 @@
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// diskState is what an editable file looked like when the TUI last read or
+// wrote it. Saves compare it with the file on disk so changes made by the
+// web UI or an agent in the meantime aren't silently overwritten.
+type diskState struct {
+	Exists  bool
+	ModTime time.Time
+	Hash    [sha256.Size]byte
+	Content string
+}
+
+// docKey identifies an editable document
+type docKey struct {
+	investigationID int
+	name            string // File name, e.g. customer-response.md
+}
+
+// readDiskState reads a file; a missing file is a state, not an error
+func readDiskState(path string) (diskState, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return diskState{}, nil
+	}
+	if err != nil {
+		return diskState{}, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return diskState{}, err
+	}
+	return diskState{Exists: true, ModTime: info.ModTime(), Hash: sha256.Sum256(content), Content: string(content)}, nil
+}
+
+// sameContent reports whether two states hold the same file. A changed
+// mtime alone (a touch, or a rewrite with identical text) is not a change.
+func (d diskState) sameContent(other diskState) bool {
+	return d.Exists == other.Exists && d.Hash == other.Hash
+}
+
+// saveConflict describes a save that found the file changed on disk
+type saveConflict struct {
+	key    docKey
+	mine   string
+	base   diskState // What the edit started from
+	theirs diskState // What is on disk now
+	// save writes mine again, expecting the given state on disk
+	save func(expected *diskState) tea.Cmd
+
+	// Diffs for the dialog, worked out once when the conflict is found
+	theirChanges []diffLine // base → theirs
+	yourChanges  []diffLine // base → mine
+	theirsVsMine []diffLine // theirs → mine
+}
+
+func newSaveConflict(mine string, base, theirs diskState) *saveConflict {
+	return &saveConflict{
+		mine:         mine,
+		base:         base,
+		theirs:       theirs,
+		theirChanges: lineDiff(base.Content, theirs.Content, 2),
+		yourChanges:  lineDiff(base.Content, mine, 2),
+		theirsVsMine: lineDiff(theirs.Content, mine, 2),
+	}
+}
+
+// writeChecked writes content to path unless the file no longer matches
+// expected. A nil expected state skips the check. It returns the new state
+// of the file, or the conflict when someone else changed it.
+func writeChecked(path, content string, expected *diskState) (diskState, *saveConflict, error) {
+	if expected != nil {
+		current, err := readDiskState(path)
+		if err != nil {
+			return diskState{}, nil, err
+		}
+		if !current.sameContent(*expected) {
+			return diskState{}, newSaveConflict(content, *expected, current), nil
+		}
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return diskState{}, nil, err
+	}
+	state := diskState{Exists: true, ModTime: time.Now(), Hash: sha256.Sum256([]byte(content)), Content: content}
+	if info, err := os.Stat(path); err == nil {
+		state.ModTime = info.ModTime()
+	}
+	return state, nil, nil
+}
+
+// beginEdit pins the file as last loaded as the base of a new edit. Loads
+// that arrive while editing keep updating diskStates but not the base.
+func (m *model) beginEdit(key docKey) {
+	if state, ok := m.diskStates[key]; ok {
+		m.editBases[key] = state
+	} else {
+		delete(m.editBases, key)
+	}
+}
+
+// settleDiskState records the file after a save, or after taking theirs;
+// the edit, if any, is over.
+func (m *model) settleDiskState(key docKey, state diskState) {
+	m.diskStates[key] = state
+	delete(m.editBases, key)
+}
+
+// expectedDiskState is the state a save of the document should find on
+// disk, or nil when the TUI never read the file.
+func (m model) expectedDiskState(key docKey) *diskState {
+	if state, ok := m.editBases[key]; ok {
+		return &state
+	}
+	if state, ok := m.diskStates[key]; ok {
+		return &state
+	}
+	return nil
+}
+
+// Conflict dialog views, cycled with d
+const (
+	conflictSummary = iota
+	conflictTheirChanges
+	conflictYourChanges
+	conflictTheirsVsMine
+	conflictViewCount
+)
+
+// openConflict replaces the save confirmation with the conflict dialog
+func (m *model) openConflict(c *saveConflict) {
+	m.closeModal(modalConfirm)
+	m.conflict = c
+	m.conflictView = conflictSummary
+	m.pushModal(modalConflict)
+}
+
+func (m model) handleConflictKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.conflict
+	if c == nil {
+		m.closeModal(modalConflict)
+		return m, nil
+	}
+	switch msg.String() {
+	case "m":
+		// Overwrite whatever is on disk now
+		m.closeModal(modalConflict)
+		m.conflict = nil
+		return m, c.save(&c.theirs)
+	case "t":
+		m.closeModal(modalConflict)
+		m.conflict = nil
+		m.takeTheirs(c)
+		return m, m.notify(severityInfo, "save "+c.key.name, "Discarded your edits and loaded the version on disk", nil)
+	case "d":
+		m.conflictView = (m.conflictView + 1) % conflictViewCount
+		return m, nil
+	case "esc":
+		// Back to editing; saving again re-checks the file
+		m.closeModal(modalConflict)
+		m.conflict = nil
+		return m, nil
+	}
+	return m, nil
+}
+
+// takeTheirs drops the edit and shows the file as it is on disk
+func (m *model) takeTheirs(c *saveConflict) {
+	id := c.key.investigationID
+	m.settleDiskState(c.key, c.theirs)
+	switch c.key.name {
+	case "customer-response.md":
+		m.editingResponse = false
+		if !c.theirs.Exists {
+			delete(m.customerResponses, id)
+		} else if response := m.customerResponses[id]; response != nil {
+			response.Content = c.theirs.Content
+			response.LastEdited = c.theirs.ModTime
+		} else {
+			m.customerResponses[id] = &CustomerResponse{Content: c.theirs.Content, LastEdited: c.theirs.ModTime}
+		}
+		m.lintSavedResponse(id)
+	case kbArticleFile:
+		m.editingKB = false
+		m.kbTextarea.Blur()
+		if !c.theirs.Exists {
+			m.kbArticles[id] = nil
+		} else {
+			path := resolveInvestigationFile(id, kbArticleFile)
+			m.kbArticles[id] = &KBArticle{Content: c.theirs.Content, Path: path, Modified: c.theirs.ModTime}
+		}
+	case "linear-draft.md":
+		var draft *LinearDraft
+		if c.theirs.Exists {
+			draft = parseLinearDraft(c.theirs.Content)
+		}
+		m.linearDrafts[id] = draft
+		m.stopDraftEdit()
+		if m.draftFormFor == id {
+			m.fillDraftForm(id, draft)
+		}
+	}
+}
+
+func (m model) renderConflictDialog() string {
+	c := m.conflict
+	if c == nil {
+		return ""
+	}
+	dialogWidth := m.modalWidth(90)
+	inner := dialogWidth - 8
+
+	header := lipgloss.NewStyle().Bold(true).Foreground(statusError).
+		Render(withIcon(glyphFailed, fmt.Sprintf("%s changed on disk", c.key.name)))
+
+	var what string
+	switch {
+	case !c.theirs.Exists:
+		what = fmt.Sprintf("#%d's %s was deleted while you were editing it.", c.key.investigationID, c.key.name)
+	case !c.base.Exists:
+		what = fmt.Sprintf("#%d's %s was created (%s) while you were editing.", c.key.investigationID, c.key.name, c.theirs.ModTime.Format("15:04:05"))
+	default:
+		added, removed := diffStats(c.theirChanges)
+		what = fmt.Sprintf("Someone else saved #%d's %s at %s while you were editing it (+%d -%d lines).",
+			c.key.investigationID, c.key.name, c.theirs.ModTime.Format("15:04:05"), added, removed)
+	}
+	message := lipgloss.NewStyle().Foreground(textPrimary).Width(inner).Render(what)
+
+	options := lipgloss.NewStyle().Foreground(textSecondary).Render(
+		"[m] Keep mine — overwrite their changes\n" +
+			"[t] Take theirs — discard your edits\n" +
+			"[d] View diffs: theirs, yours, theirs vs yours\n" +
+			"[Esc] Back to editing")
+
+	var diffTitle, diff string
+	maxLines := m.modalHeight(36) - 16
+	switch m.conflictView {
+	case conflictTheirsVsMine:
+		diffTitle = "On disk (-) vs your version (+)"
+		diff = renderDiff(c.theirsVsMine, inner, maxLines)
+	case conflictTheirChanges:
+		diffTitle = "Their changes since you started editing"
+		diff = renderDiff(c.theirChanges, inner, maxLines)
+	case conflictYourChanges:
+		diffTitle = "Your changes"
+		diff = renderDiff(c.yourChanges, inner, maxLines)
+	}
+
+	parts := []string{header, "", message, ""}
+	if diffTitle != "" {
+		parts = append(parts, sectionHeaderStyle.Render(diffTitle), diff, "")
+	}
+	parts = append(parts, options)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(statusError).
+		BorderBackground(bgPrimary).
+		Background(bgPrimary).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
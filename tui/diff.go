@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// diffLine is one line of a line-based diff. Kind is ' ' for context, '-'
+// for a removed line, '+' for an added line and '@' for a hunk marker.
+type diffLine struct {
+	Kind byte
+	Text string
+}
+
+// diffLimit caps the LCS table; bigger inputs are shown as a full
+// replacement rather than stalling the UI.
+const diffLimit = 4_000_000
+
+// lineDiff compares two documents line by line, keeping context lines of
+// unchanged text around each change. It returns nil when they're equal.
+func lineDiff(before, after string, context int) []diffLine {
+	a := strings.Split(strings.TrimRight(before, "\n"), "\n")
+	b := strings.Split(strings.TrimRight(after, "\n"), "\n")
+	if before == after || strings.TrimRight(before, "\n") == strings.TrimRight(after, "\n") {
+		return nil
+	}
+
+	var ops []diffLine
+	if len(a)*len(b) > diffLimit {
+		for _, line := range a {
+			ops = append(ops, diffLine{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffLine{'+', line})
+		}
+	} else {
+		// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(a) && j < len(b) {
+			switch {
+			case a[i] == b[j]:
+				ops = append(ops, diffLine{' ', a[i]})
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				ops = append(ops, diffLine{'-', a[i]})
+				i++
+			default:
+				ops = append(ops, diffLine{'+', b[j]})
+				j++
+			}
+		}
+		for ; i < len(a); i++ {
+			ops = append(ops, diffLine{'-', a[i]})
+		}
+		for ; j < len(b); j++ {
+			ops = append(ops, diffLine{'+', b[j]})
+		}
+	}
+
+	// Keep only context lines near a change
+	keep := make([]bool, len(ops))
+	for k, op := range ops {
+		if op.Kind == ' ' {
+			continue
+		}
+		for c := k - context; c <= k+context; c++ {
+			if c >= 0 && c < len(ops) {
+				keep[c] = true
+			}
+		}
+	}
+	var out []diffLine
+	for k, op := range ops {
+		if !keep[k] {
+			continue
+		}
+		if k > 0 && !keep[k-1] && len(out) > 0 {
+			out = append(out, diffLine{Kind: '@'})
+		}
+		out = append(out, op)
+	}
+	return out
+}
+
+// diffStats counts added and removed lines
+func diffStats(lines []diffLine) (added, removed int) {
+	for _, l := range lines {
+		switch l.Kind {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// renderDiff colours a diff for display, truncating long lines to width and
+// showing at most maxLines (0 means no limit).
+func renderDiff(lines []diffLine, width, maxLines int) string {
+	if len(lines) == 0 {
+		return dimmedTextStyle.Render("No changes")
+	}
+	removedStyle := lipgloss.NewStyle().Foreground(statusError)
+	addedStyle := lipgloss.NewStyle().Foreground(statusCompleted)
+
+	var out []string
+	for k, l := range lines {
+		if maxLines > 0 && k == maxLines-1 && len(lines) > maxLines {
+			out = append(out, dimmedTextStyle.Render(fmt.Sprintf("… %d more lines", len(lines)-k)))
+			break
+		}
+		text := truncateStr(string(l.Kind)+" "+l.Text, width)
+		switch l.Kind {
+		case '-':
+			out = append(out, removedStyle.Render(text))
+		case '+':
+			out = append(out, addedStyle.Render(text))
+		case '@':
+			out = append(out, dimmedTextStyle.Render("  ..."))
+		default:
+			out = append(out, dimmedTextStyle.Render(text))
+		}
+	}
+	return strings.Join(out, "\n")
+}
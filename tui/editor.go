@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Documents that can be opened in an external editor
+const (
+	externalResponse    = "response"
+	externalLinearDraft = "linear_draft"
+	externalKB          = "kb"
+)
+
+// externalEdit is a document handed to $EDITOR. original is what was written
+// to the temp file, so the result can be diffed against it.
+type externalEdit struct {
+	kind            string
+	investigationID int
+	name            string // File the document is saved to, e.g. customer-response.md
+	original        string
+	path            string // Temp file
+}
+
+// editorCommand returns $VISUAL or $EDITOR split into argv, falling back to
+// vi. Values like "code --wait" keep their arguments.
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// Write the document to a temp file and suspend the UI while the editor runs.
+// The temp file is removed afterwards unless the editor failed, in which case
+// it is kept so nothing typed is lost.
+func openExternalEditorCmd(doc externalEdit) tea.Cmd {
+	f, err := os.CreateTemp("", fmt.Sprintf("triage-%d-*-%s", doc.investigationID, doc.name))
+	if err == nil {
+		_, err = f.WriteString(doc.original)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return func() tea.Msg {
+			return externalEditDoneMsg{doc: doc, err: fmt.Errorf("write temp file: %w", err)}
+		}
+	}
+	doc.path = f.Name()
+
+	argv := append(editorCommand(), doc.path)
+	cmd := exec.Command(argv[0], argv[1:]...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				err = fmt.Errorf("%s exited with status %d", argv[0], exitErr.ExitCode())
+			} else {
+				// The editor never ran, so there is nothing to keep
+				os.Remove(doc.path)
+			}
+			return externalEditDoneMsg{doc: doc, err: err}
+		}
+		content, err := os.ReadFile(doc.path)
+		if err != nil {
+			return externalEditDoneMsg{doc: doc, err: fmt.Errorf("read back %s: %w", doc.path, err)}
+		}
+		os.Remove(doc.path)
+		return externalEditDoneMsg{doc: doc, content: string(content)}
+	})
+}
+
+// openExternalEditor picks the document for the active tab: the customer
+// response on Summary, the draft form on Linear Draft and the KB article.
+func (m *model) openExternalEditor() tea.Cmd {
+	inv := m.getSelectedInvestigation()
+	if inv == nil {
+		return nil
+	}
+	doc := externalEdit{investigationID: inv.ID}
+	switch m.activeTab {
+	case TabSummary:
+		response := m.getCustomerResponse(inv.ID)
+		if response == nil {
+			return m.notify(severityInfo, "external editor", "No customer response to edit yet", nil)
+		}
+		doc.kind, doc.name, doc.original = externalResponse, "customer-response.md", response.Content
+	case TabLinearDraft:
+		if m.draftFormFor != inv.ID {
+			return nil
+		}
+		// Start from the form so unsaved edits carry over
+		doc.kind, doc.name, doc.original = externalLinearDraft, "linear-draft.md", m.formDraft().Markdown()
+	case TabKB:
+		article := m.kbArticles[inv.ID]
+		if article == nil {
+			return m.notify(severityInfo, "external editor", "No KB article to edit yet", nil)
+		}
+		doc.kind, doc.name, doc.original = externalKB, kbArticleFile, article.Content
+	default:
+		return nil
+	}
+	if doc.kind != externalLinearDraft {
+		// The draft form's edit started when it was filled
+		m.beginEdit(docKey{inv.ID, doc.name})
+	}
+	return openExternalEditorCmd(doc)
+}
+
+// finishExternalEdit loads the edited document into its in-app editor and
+// asks to save it, showing what changed. Failed editors and unchanged
+// documents leave everything as it was.
+func (m *model) finishExternalEdit(msg externalEditDoneMsg) tea.Cmd {
+	doc := msg.doc
+	if msg.err != nil {
+		text := msg.err.Error() + "; changes were not applied"
+		if doc.path != "" {
+			if _, err := os.Stat(doc.path); err == nil {
+				text += " (your edits are in " + doc.path + ")"
+			}
+		}
+		return m.notify(severityWarning, "external editor", text, nil)
+	}
+	diff := lineDiff(doc.original, msg.content, 2)
+	if diff == nil {
+		return m.notify(severityInfo, "external editor", "No changes to "+doc.name, nil)
+	}
+	if inv := m.getSelectedInvestigation(); inv == nil || inv.ID != doc.investigationID {
+		return m.notify(severityWarning, "external editor",
+			fmt.Sprintf("#%d is no longer selected; edits to %s were discarded", doc.investigationID, doc.name), nil)
+	}
+
+	// The in-app editor holds the edit until it is saved, so answering no
+	// to the confirmation keeps it for further changes.
+	content := strings.TrimRight(msg.content, "\n")
+	added, removed := diffStats(diff)
+	switch doc.kind {
+	case externalResponse:
+		m.editingResponse = true
+		m.responseTextarea.SetValue(content)
+		m.responseTextarea.Focus()
+		m.lintEditedResponse()
+		m.pushModal(modalConfirm)
+		m.confirmAction = "save"
+		m.confirmMessage = "Save changes to customer response?"
+	case externalKB:
+		m.editingKB = true
+		m.kbTextarea.SetValue(content)
+		m.kbTextarea.Focus()
+		m.pushModal(modalConfirm)
+		m.confirmAction = "save_kb"
+		m.confirmMessage = "Save changes to the KB article?"
+	case externalLinearDraft:
+		m.fillDraftForm(doc.investigationID, parseLinearDraft(msg.content))
+		m.draftDirty = true
+		m.startDraftEdit()
+		m.draftProblems = m.formDraft().validate()
+		m.pushModal(modalConfirm)
+		m.confirmAction = "save_draft"
+		m.confirmMessage = "Save changes to the Linear draft?"
+	}
+	m.confirmMessage += fmt.Sprintf(" (+%d -%d lines)", added, removed)
+	m.confirmDiff = diff
+	return nil
+}
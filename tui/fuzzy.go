@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fuzzyMatch reports whether every character of query appears in target in
+// order, ignoring case and spaces in the query. Higher scores are better:
+// runs of consecutive characters and matches at the start of a word count
+// for more, and skipped characters count against.
+func fuzzyMatch(query, target string) (int, bool) {
+	q := []rune(strings.ToLower(strings.Join(strings.Fields(query), "")))
+	if len(q) == 0 {
+		return 0, true
+	}
+	t := []rune(strings.ToLower(target))
+
+	score, qi, last := 0, 0, -1
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		score += 10
+		switch {
+		case ti == 0, !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]):
+			score += 8 // Start of a word
+		case last == ti-1:
+			score += 6 // Continues the previous match
+		}
+		if last >= 0 {
+			score -= ti - last - 1
+		}
+		last = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score, true
+}
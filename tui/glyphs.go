@@ -0,0 +1,196 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+)
+
+// glyphID identifies an icon in the glyph table. Every icon the UI draws is
+// looked up through glyph() so the emoji and ASCII sets stay in sync.
+type glyphID int
+
+const (
+	glyphStatusRunning glyphID = iota
+	glyphStatusWaiting
+	glyphStatusCompleted
+	glyphStatusError
+	glyphStatusPending
+	glyphAgentSlack
+	glyphAgentLinear
+	glyphAgentPylon
+	glyphAgentCodebase
+	glyphAgentOther
+	glyphTabSummary
+	glyphTabLinearDraft
+	glyphTabKB
+	glyphReply
+	glyphFinding
+	glyphPaused
+	glyphDone
+	glyphFailed
+	glyphActive
+	glyphRootCause
+	glyphKeyFindings
+	glyphQuestions
+	glyphNextSteps
+	glyphEdit
+	glyphReset
+	glyphPost
+	glyphSave
+	glyphStalled
+	glyphCount
+)
+
+// emojiGlyphs is the default icon set. Entries avoid variation-selector
+// sequences (e.g. "➡️") because runewidth measures them narrower than
+// terminals draw them, which throws off the fixed-height layout.
+var emojiGlyphs = [glyphCount]string{
+	glyphStatusRunning:   "🟡",
+	glyphStatusWaiting:   "🔵",
+	glyphStatusCompleted: "🟢",
+	glyphStatusError:     "🔴",
+	glyphStatusPending:   "○",
+	glyphAgentSlack:      "💬",
+	glyphAgentLinear:     "📋",
+	glyphAgentPylon:      "🎫",
+	glyphAgentCodebase:   "💻",
+	glyphAgentOther:      "🔧",
+	glyphTabSummary:      "📊",
+	glyphTabLinearDraft:  "📄",
+	glyphTabKB:           "📝",
+	glyphReply:           "📩",
+	glyphFinding:         "📌",
+	glyphPaused:          "⏸",
+	glyphDone:            "✅",
+	glyphFailed:          "✖",
+	glyphActive:          "🔄",
+	glyphRootCause:       "🔍",
+	glyphKeyFindings:     "📋",
+	glyphQuestions:       "❓",
+	glyphNextSteps:       "👉",
+	glyphEdit:            "📝",
+	glyphReset:           "🔄",
+	glyphPost:            "📤",
+	glyphSave:            "💾",
+	glyphStalled:         "⏳",
+}
+
+// asciiGlyphs is used on terminals that can't be trusted with emoji widths
+// (linux console, tmux with mismatched wcwidth, non-UTF-8 locales). Agent and
+// tab icons are empty since the label already names them.
+var asciiGlyphs = [glyphCount]string{
+	glyphStatusRunning:   "[~]",
+	glyphStatusWaiting:   "[?]",
+	glyphStatusCompleted: "[+]",
+	glyphStatusError:     "[x]",
+	glyphStatusPending:   "[ ]",
+	glyphReply:           "[>]",
+	glyphFinding:         "*",
+	glyphPaused:          "||",
+	glyphDone:            "[ok]",
+	glyphFailed:          "x",
+	glyphActive:          "~",
+	glyphRootCause:       ">",
+	glyphKeyFindings:     ">",
+	glyphQuestions:       ">",
+	glyphNextSteps:       ">",
+	glyphEdit:            ">",
+	glyphReset:           ">",
+	glyphPost:            ">",
+	glyphSave:            ">",
+	glyphStalled:         "[!]",
+}
+
+// asciiMode is set once at startup; glyphs points at the matching table.
+var (
+	asciiMode = false
+	glyphs    = &emojiGlyphs
+)
+
+// asciiReplacer maps the single-cell Unicode punctuation and box-drawing
+// characters used by our strings, lipgloss borders and bubbles widgets to
+// single-cell ASCII, so rendered widths are unchanged by the substitution.
+var asciiReplacer = strings.NewReplacer(
+	"─", "-", "━", "-", "│", "|", "┃", "|",
+	"╭", "+", "╮", "+", "╰", "+", "╯", "+",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+",
+	"├", "+", "┤", "+", "┬", "+", "┴", "+", "┼", "+",
+	"•", "*", "—", "-", "…", ".",
+	"→", ">", "←", "<", "↑", "^", "↓", "v",
+	"▸", ">", "▾", "v", "○", "o", "↻", "r",
+)
+
+func glyph(id glyphID) string {
+	return glyphs[id]
+}
+
+// withIcon prefixes text with an icon, skipping the separator when the active
+// glyph set has no icon for it.
+func withIcon(id glyphID, text string) string {
+	icon := glyph(id)
+	if icon == "" {
+		return text
+	}
+	return icon + " " + text
+}
+
+// setASCIIMode switches the active glyph table. Call before initialModel so
+// the spinner picks up the matching frames.
+func setASCIIMode(enabled bool) {
+	asciiMode = enabled
+	if enabled {
+		glyphs = &asciiGlyphs
+	} else {
+		glyphs = &emojiGlyphs
+	}
+}
+
+// toASCII rewrites a fully rendered frame for ASCII mode. It is a no-op when
+// emoji rendering is active.
+func toASCII(s string) string {
+	if !asciiMode {
+		return s
+	}
+	return asciiReplacer.Replace(s)
+}
+
+// spinnerFrames returns the spinner animation matching the glyph set
+// (the default dot spinner uses braille characters).
+func spinnerFrames() spinner.Spinner {
+	if asciiMode {
+		return spinner.Line
+	}
+	return spinner.Dot
+}
+
+// detectASCIIMode decides the glyph set from the environment.
+// TUI_ASCII=1/0 forces it either way; otherwise ASCII is chosen for the
+// linux console, dumb/vt terminals and explicit non-UTF-8 locales.
+func detectASCIIMode() bool {
+	switch strings.ToLower(os.Getenv("TUI_ASCII")) {
+	case "1", "true", "yes":
+		return true
+	case "0", "false", "no":
+		return false
+	}
+
+	term := os.Getenv("TERM")
+	if term == "linux" || term == "dumb" || strings.HasPrefix(term, "vt") {
+		return true
+	}
+
+	locale := ""
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			locale = v
+			break
+		}
+	}
+	if locale == "" {
+		return false // Unknown — keep the default emoji set
+	}
+	lower := strings.ToLower(locale)
+	return !strings.Contains(lower, "utf-8") && !strings.Contains(lower, "utf8")
+}
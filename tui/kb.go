@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// kbArticleFile is what the kb-article skill writes into the investigation dir
+const kbArticleFile = "kb-article.md"
+
+// KBArticle is a generated knowledge base article
+type KBArticle struct {
+	Content  string
+	Path     string
+	Modified time.Time
+}
+
+// kbSection is one ## section of an article; the text before the first
+// ## heading is the intro.
+type kbSection struct {
+	Heading string
+	Body    string
+}
+
+// Title is the first # heading, if any
+func (a *KBArticle) Title() string {
+	for _, line := range strings.Split(a.Content, "\n") {
+		if strings.HasPrefix(line, "# ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		}
+	}
+	return ""
+}
+
+// Sections splits the article on ## headings
+func (a *KBArticle) Sections() []kbSection {
+	var sections []kbSection
+	current := kbSection{Heading: "Intro"}
+	var body []string
+	flush := func() {
+		current.Body = strings.TrimSpace(strings.Join(body, "\n"))
+		if current.Body != "" || current.Heading != "Intro" {
+			sections = append(sections, current)
+		}
+		body = nil
+	}
+	for _, line := range strings.Split(a.Content, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			flush()
+			current = kbSection{Heading: strings.TrimSpace(strings.TrimPrefix(line, "## "))}
+			continue
+		}
+		if strings.HasPrefix(line, "# ") {
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	return sections
+}
+
+// kbExportDir is where exported articles go; TUI_EXPORT_DIR overrides it
+func kbExportDir() string {
+	if dir := os.Getenv("TUI_EXPORT_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(filepath.Dir(investigationsDir), "exports")
+}
+
+var slugUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+func slugify(s string) string {
+	slug := strings.Trim(slugUnsafe.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(slug) > 60 {
+		slug = strings.TrimRight(slug[:60], "-")
+	}
+	return slug
+}
+
+// Load kb-article.md. A missing file is not an error: most investigations
+// don't have one.
+func loadKBArticleCmd(ctx context.Context, investigationID, gen int) tea.Cmd {
+	return func() tea.Msg {
+		path := resolveInvestigationFile(investigationID, kbArticleFile)
+		if path == "" {
+			return kbArticleLoadedMsg{investigationID: investigationID, gen: gen}
+		}
+		disk, err := readDiskState(path)
+		if ctx.Err() != nil {
+			return nil // The selection moved on while this was reading
+		}
+		if err != nil {
+			return errMsg{err: err, source: "load KB article", retry: reloadCmd(loadKBArticle, investigationID, ""), poll: true}
+		}
+		return kbArticleLoadedMsg{
+			investigationID: investigationID,
+			article:         &KBArticle{Content: disk.Content, Path: path, Modified: disk.ModTime},
+			disk:            disk,
+			gen:             gen,
+		}
+	}
+}
+
+// Save the edited article back to kb-article.md, unless it changed on disk
+// since editing started
+func saveKBArticleCmd(investigationID int, content string, expected *diskState) tea.Cmd {
+	return func() tea.Msg {
+		path := resolveInvestigationFile(investigationID, kbArticleFile)
+		if path == "" {
+			path = fmt.Sprintf("%s/%d/%s", investigationsDir, investigationID, kbArticleFile)
+		}
+		disk, conflict, err := writeChecked(path, content, expected)
+		if err != nil {
+			return errMsg{err: err, source: "save KB article", retry: saveKBArticleCmd(investigationID, content, expected)}
+		}
+		if conflict != nil {
+			conflict.key = docKey{investigationID, kbArticleFile}
+			conflict.save = func(expected *diskState) tea.Cmd {
+				return saveKBArticleCmd(investigationID, content, expected)
+			}
+			return saveConflictMsg{conflict: conflict}
+		}
+		return kbArticleSavedMsg{
+			investigationID: investigationID,
+			article:         &KBArticle{Content: content, Path: path, Modified: disk.ModTime},
+			disk:            disk,
+		}
+	}
+}
+
+// Export the article as a standalone markdown file named after its title
+func exportKBArticleCmd(investigationID int, article *KBArticle) tea.Cmd {
+	return func() tea.Msg {
+		dir := kbExportDir()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return kbExportedMsg{investigationID: investigationID, err: err}
+		}
+		name := fmt.Sprintf("kb-%d.md", investigationID)
+		if slug := slugify(article.Title()); slug != "" {
+			name = fmt.Sprintf("kb-%d-%s.md", investigationID, slug)
+		}
+		path := filepath.Join(dir, name)
+		content := strings.TrimRight(article.Content, "\n") + "\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return kbExportedMsg{investigationID: investigationID, err: err}
+		}
+		return kbExportedMsg{investigationID: investigationID, path: path}
+	}
+}
+
+// Ask the server to draft a KB article for a finished investigation. The
+// request returns once kb-article.md has been written.
+func generateKBArticleCmd(investigationID int) tea.Cmd {
+	return func() tea.Msg {
+		path := fmt.Sprintf("/api/investigations/%d/kb-article", investigationID)
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		defer cancel()
+		if err := api.post(ctx, path, nil, nil); err != nil {
+			if endpointMissing(err) {
+				err = fmt.Errorf("server has no %s endpoint; update the UI server", path)
+			}
+			return kbGeneratedMsg{investigationID: investigationID, err: err}
+		}
+		return kbGeneratedMsg{investigationID: investigationID}
+	}
+}
+
+// startKBEdit opens the article in the textarea
+func (m *model) startKBEdit(investigationID int, article *KBArticle) tea.Cmd {
+	m.editingKB = true
+	m.beginEdit(docKey{investigationID, kbArticleFile})
+	m.kbTextarea.SetValue(article.Content)
+	return m.kbTextarea.Focus()
+}
+
+// handleKBEditKey handles keys while the article is being edited
+func (m model) handleKBEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Save):
+		m.pushModal(modalConfirm)
+		m.confirmAction = "save_kb"
+		m.confirmMessage = "Save changes to the KB article?"
+		return m, nil
+
+	case key.Matches(msg, keys.Escape):
+		m.editingKB = false
+		m.kbTextarea.Blur()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.kbTextarea, cmd = m.kbTextarea.Update(msg)
+	return m, cmd
+}
+
+// requestKBGeneration starts generation when the investigation is done and
+// has no article yet.
+func (m *model) requestKBGeneration(inv *Investigation) tea.Cmd {
+	switch {
+	case m.kbArticles[inv.ID] != nil:
+		return nil
+	case m.kbGenerating[inv.ID]:
+		return m.notify(severityInfo, "generate KB article", fmt.Sprintf("Already generating a KB draft for #%d", inv.ID), nil)
+	case inv.Status != "complete":
+		return m.notify(severityWarning, "generate KB article", "KB drafts can only be generated for completed investigations", nil)
+	}
+	m.kbGenerating[inv.ID] = true
+	return tea.Batch(
+		generateKBArticleCmd(inv.ID),
+		m.notify(severityInfo, "generate KB article", fmt.Sprintf("Generating a KB draft for #%d...", inv.ID), nil),
+	)
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// learningDir holds the edit dataset; set from -state-dir in main. It has
+// generated/<id>-run<N>.md, the response as the agent first wrote it, and
+// response-edits.jsonl, one line per edited response saved or posted.
+var learningDir = filepath.Join(defaultStateDir(), "learning")
+
+// editDatasetFile is the JSONL dataset inside learningDir
+const editDatasetFile = "response-edits.jsonl"
+
+// responseEditPair is one dataset line: what the agent generated and what
+// a human saved or posted instead
+type responseEditPair struct {
+	InvestigationID int       `json:"investigation_id"`
+	RunNumber       int       `json:"run_number"`
+	Event           string    `json:"event"` // "saved" or "posted"
+	Classification  string    `json:"classification"`
+	ProductArea     string    `json:"product_area"`
+	ConnectorName   string    `json:"connector_name,omitempty"`
+	EditKinds       []string  `json:"edit_kinds"`
+	LinesAdded      int       `json:"lines_added"`
+	LinesRemoved    int       `json:"lines_removed"`
+	Original        string    `json:"original"`
+	Final           string    `json:"final"`
+	RecordedAt      time.Time `json:"recorded_at"`
+}
+
+// generatedPath is where the generated response for a run is kept
+func generatedPath(dir string, investigationID, run int) string {
+	return filepath.Join(dir, "generated", fmt.Sprintf("%d-run%d.md", investigationID, run))
+}
+
+// Keep the first version of a run's response the TUI sees as the generated
+// one. A response already edited in the web UI before the TUI loaded it is
+// taken as generated; there is no earlier copy to compare with.
+func recordGeneratedResponseCmd(dir string, investigationID, run int, content string) tea.Cmd {
+	return func() tea.Msg {
+		path := generatedPath(dir, investigationID, run)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return editCapturedMsg{err: err}
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, os.ErrExist) {
+			return nil
+		}
+		if err != nil {
+			return editCapturedMsg{err: err}
+		}
+		_, err = f.WriteString(content)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return editCapturedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// Append the generated and final response as a pair. Nothing is recorded
+// when there is no generated copy or the final text is unchanged.
+func captureEditCmd(dir string, pair responseEditPair) tea.Cmd {
+	return func() tea.Msg {
+		original, err := os.ReadFile(generatedPath(dir, pair.InvestigationID, pair.RunNumber))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return editCapturedMsg{err: err}
+		}
+		pair.Original = string(original)
+		diff := lineDiff(pair.Original, pair.Final, 0)
+		if diff == nil {
+			return nil
+		}
+		pair.LinesAdded, pair.LinesRemoved = diffStats(diff)
+		pair.EditKinds = classifyEdit(pair.Original, pair.Final)
+		pair.RecordedAt = time.Now()
+
+		line, err := json.Marshal(pair)
+		if err != nil {
+			return editCapturedMsg{err: err}
+		}
+		f, err := os.OpenFile(filepath.Join(dir, editDatasetFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return editCapturedMsg{err: err}
+		}
+		_, err = f.Write(append(line, '\n'))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return editCapturedMsg{err: err}
+	}
+}
+
+// learnFromEdits is customer_response_style.learn_from_edits
+func (m model) learnFromEdits() bool {
+	return m.settings.responseStyle().LearnFromEdits
+}
+
+// rememberGenerated records a loaded response as its run's generated copy,
+// once per run per session
+func (m *model) rememberGenerated(investigationID int, content string) tea.Cmd {
+	inv := m.findInvestigation(investigationID)
+	if !m.learnFromEdits() || inv == nil || strings.TrimSpace(content) == "" {
+		return nil
+	}
+	key := fmt.Sprintf("%d-run%d", investigationID, inv.CurrentRunNumber)
+	if m.generatedRecorded[key] {
+		return nil
+	}
+	m.generatedRecorded[key] = true
+	return recordGeneratedResponseCmd(learningDir, investigationID, inv.CurrentRunNumber, content)
+}
+
+// captureEdit records a saved or posted response against the generated one
+func (m model) captureEdit(investigationID int, event, final string) tea.Cmd {
+	inv := m.findInvestigation(investigationID)
+	if !m.learnFromEdits() || inv == nil {
+		return nil
+	}
+	pair := responseEditPair{
+		InvestigationID: investigationID,
+		RunNumber:       inv.CurrentRunNumber,
+		Event:           event,
+		Classification:  inv.Classification,
+		ProductArea:     inv.ProductArea,
+		ConnectorName:   inv.ConnectorName,
+		Final:           final,
+	}
+	if td := m.ticketData[investigationID]; td != nil {
+		if td.Classification != "" {
+			pair.Classification = td.Classification
+		}
+		if td.ProductArea != "" {
+			pair.ProductArea = td.ProductArea
+		}
+		if td.ConnectorName != nil && *td.ConnectorName != "" {
+			pair.ConnectorName = *td.ConnectorName
+		}
+	}
+	return captureEditCmd(learningDir, pair)
+}
+
+// Patterns the edit classifier counts before and after
+var (
+	apologyPattern  = regexp.MustCompile(`(?i)\b(?:sorry|apologi[sz]e|apologies|unfortunately)\b`)
+	timelinePattern = regexp.MustCompile(`(?i)\b(?:eta|by (?:monday|tuesday|wednesday|thursday|friday|end of (?:the )?(?:day|week))|within \d+ (?:hours?|days?|weeks?)|next (?:week|release|sprint))\b`)
+	linkPattern     = regexp.MustCompile(`https?://\S+`)
+	markupPattern   = regexp.MustCompile("[*_#`>-]+")
+)
+
+// classifyEdit names the kinds of change between the generated and final
+// response, e.g. "shortened" or "internal ref removed". Edits that
+// fit no other kind are "reworded".
+func classifyEdit(original, final string) []string {
+	// Markup and whitespace only
+	plain := func(s string) string {
+		return strings.Join(strings.Fields(markupPattern.ReplaceAllString(s, " ")), " ")
+	}
+	if plain(original) == plain(final) {
+		return []string{"formatting"}
+	}
+
+	var kinds []string
+	if firstLine(original) != firstLine(final) {
+		kinds = append(kinds, "greeting")
+	}
+	if lastLine(original) != lastLine(final) {
+		kinds = append(kinds, "sign-off")
+	}
+
+	before, after := len(strings.Fields(original)), len(strings.Fields(final))
+	switch {
+	case float64(after) < float64(before)*0.85:
+		kinds = append(kinds, "shortened")
+	case float64(after) > float64(before)*1.15:
+		kinds = append(kinds, "expanded")
+	}
+
+	countRule := func(s, rule string) int {
+		n := 0
+		for _, f := range lintResponse(s, ResponseStyleSettings{}) {
+			if f.Rule == rule {
+				n++
+			}
+		}
+		return n
+	}
+	fewer := func(pattern *regexp.Regexp) bool {
+		return len(pattern.FindAllString(final, -1)) < len(pattern.FindAllString(original, -1))
+	}
+	if countRule(final, "internal-ref") < countRule(original, "internal-ref") {
+		kinds = append(kinds, "internal ref removed")
+	}
+	if countRule(final, "placeholder") < countRule(original, "placeholder") {
+		kinds = append(kinds, "placeholder filled")
+	}
+	if fewer(apologyPattern) {
+		kinds = append(kinds, "apology removed")
+	}
+	if fewer(timelinePattern) {
+		kinds = append(kinds, "timeline removed")
+	}
+	switch links := len(linkPattern.FindAllString(final, -1)) - len(linkPattern.FindAllString(original, -1)); {
+	case links > 0:
+		kinds = append(kinds, "link added")
+	case links < 0:
+		kinds = append(kinds, "link removed")
+	}
+	if len(kinds) == 0 {
+		kinds = append(kinds, "reworded")
+	}
+	return kinds
+}
+
+// lastLine is the last non-blank line
+func lastLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		return strings.TrimSpace(s[i+1:])
+	}
+	return s
+}
+
+// editInsights summarizes the dataset for the insights view
+type editInsights struct {
+	path             string
+	pairs            []responseEditPair // Latest pair per investigation run, newest first
+	tickets          int
+	kinds            []countStat
+	byClassification []classificationEdits
+	err              error
+}
+
+// classificationEdits is the commonest edits for one classification
+type classificationEdits struct {
+	classification string
+	responses      int
+	kinds          []countStat
+}
+
+// Read the dataset and count edit kinds. A run saved several times counts
+// once, by its latest pair.
+func loadEditInsightsCmd(dir string) tea.Cmd {
+	return func() tea.Msg {
+		path := filepath.Join(dir, editDatasetFile)
+		report := &editInsights{path: path}
+		f, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return editInsightsLoadedMsg{report: report}
+		}
+		if err != nil {
+			report.err = err
+			return editInsightsLoadedMsg{report: report}
+		}
+		defer f.Close()
+
+		latest := map[[2]int]responseEditPair{}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			var pair responseEditPair
+			if json.Unmarshal(scanner.Bytes(), &pair) != nil {
+				continue
+			}
+			latest[[2]int{pair.InvestigationID, pair.RunNumber}] = pair
+		}
+		report.err = scanner.Err()
+
+		tickets := map[int]bool{}
+		kindCounts := map[string]int{}
+		byClass := map[string]map[string]int{}
+		classResponses := map[string]int{}
+		for _, pair := range latest {
+			report.pairs = append(report.pairs, pair)
+			tickets[pair.InvestigationID] = true
+			class := pair.Classification
+			if class == "" {
+				class = "unclassified"
+			}
+			classResponses[class]++
+			if byClass[class] == nil {
+				byClass[class] = map[string]int{}
+			}
+			for _, kind := range pair.EditKinds {
+				kindCounts[kind]++
+				byClass[class][kind]++
+			}
+		}
+		sort.Slice(report.pairs, func(i, j int) bool { return report.pairs[i].RecordedAt.After(report.pairs[j].RecordedAt) })
+		report.tickets = len(tickets)
+		report.kinds = rankCounts(kindCounts)
+		for class, counts := range byClass {
+			report.byClassification = append(report.byClassification,
+				classificationEdits{classification: class, responses: classResponses[class], kinds: rankCounts(counts)})
+		}
+		sort.Slice(report.byClassification, func(i, j int) bool {
+			a, b := report.byClassification[i], report.byClassification[j]
+			if a.responses != b.responses {
+				return a.responses > b.responses
+			}
+			return a.classification < b.classification
+		})
+		return editInsightsLoadedMsg{report: report}
+	}
+}
+
+// rankCounts orders counts, largest first
+func rankCounts(counts map[string]int) []countStat {
+	stats := make([]countStat, 0, len(counts))
+	for label, n := range counts {
+		stats = append(stats, countStat{label: label, count: n})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].count != stats[j].count {
+			return stats[i].count > stats[j].count
+		}
+		return stats[i].label < stats[j].label
+	})
+	return stats
+}
+
+// openEditInsights shows the summary of the edit dataset
+func (m *model) openEditInsights() tea.Cmd {
+	m.pushModal(modalEditInsights)
+	m.editInsightsLoading = true
+	return loadEditInsightsCmd(learningDir)
+}
+
+func (m model) handleEditInsightsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Escape), key.Matches(msg, keys.Insights), key.Matches(msg, keys.Quit):
+		m.closeModal(modalEditInsights)
+		return m, nil
+	case key.Matches(msg, keys.Refresh):
+		return m, m.openEditInsights()
+	}
+	return m, nil
+}
+
+func (m model) renderEditInsights() string {
+	dialogWidth := m.modalWidth(100)
+	dialogHeight := m.modalHeight(36)
+	inner := dialogWidth - 6
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(c1Primary).Render("Response Edit Insights")
+	report := m.editInsights
+	var body string
+	switch {
+	case report == nil:
+		body = fmt.Sprintf("%s Reading edits...", m.spinner.View())
+	default:
+		body = renderEditInsightsReport(report, inner)
+	}
+	if !m.learnFromEdits() {
+		body = lipgloss.NewStyle().Foreground(statusRunning).Width(inner).
+			Render("customer_response_style.learn_from_edits is off, so new edits aren't being recorded.") + "\n\n" + body
+	}
+
+	status := ""
+	if m.editInsightsLoading && report != nil {
+		status = m.spinner.View() + " refreshing"
+	}
+	header := title + "  " + dimmedTextStyle.Render(status)
+	footer := dimmedTextStyle.Render("r: refresh • Esc: close")
+
+	content := lipgloss.NewStyle().Height(dialogHeight - 6).MaxHeight(dialogHeight - 6).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", body))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(c1Primary).
+		BorderBackground(bgPrimary).
+		Background(bgPrimary).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(lipgloss.JoinVertical(lipgloss.Left, content, footer))
+}
+
+func renderEditInsightsReport(r *editInsights, width int) string {
+	heading := lipgloss.NewStyle().Bold(true).Foreground(textPrimary)
+	muted := lipgloss.NewStyle().Foreground(textMuted)
+	var lines []string
+
+	if r.err != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(statusError).Render("Couldn't read all edits: "+r.err.Error()), "")
+	}
+	if len(r.pairs) == 0 {
+		lines = append(lines,
+			dimmedTextStyle.Render("No edited responses recorded yet."),
+			muted.Render("Saving or posting a changed customer response adds it to "+truncateStr(r.path, width-45)))
+		return strings.Join(lines, "\n")
+	}
+
+	added, removed := 0, 0
+	for _, p := range r.pairs {
+		added += p.LinesAdded
+		removed += p.LinesRemoved
+	}
+	lines = append(lines,
+		fmt.Sprintf("%d edited responses across %d tickets • avg +%d -%d lines",
+			len(r.pairs), r.tickets, added/len(r.pairs), removed/len(r.pairs)),
+		muted.Render(truncateStr(r.path, width)), "")
+
+	lines = append(lines, renderBreakdown("Most common edits", r.kinds, 24), "")
+
+	lines = append(lines, heading.Render("By classification"))
+	for _, c := range r.byClassification {
+		var top []string
+		for i, k := range c.kinds {
+			if i == 3 {
+				break
+			}
+			top = append(top, fmt.Sprintf("%s %d", k.label, k.count))
+		}
+		lines = append(lines, truncateStr(fmt.Sprintf("%-18s %3d  %s", truncateStr(c.classification, 18), c.responses, strings.Join(top, " • ")), width))
+	}
+	lines = append(lines, "", heading.Render("Recent edits"))
+	for i, p := range r.pairs {
+		if i == 5 {
+			break
+		}
+		lines = append(lines, truncateStr(fmt.Sprintf("#%-6d %-12s %-7s +%-3d -%-3d %s",
+			p.InvestigationID, p.RecordedAt.Local().Format("Jan 2 15:04"), p.Event, p.LinesAdded, p.LinesRemoved,
+			strings.Join(p.EditKinds, ", ")), width))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,520 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// LinearDraft is linear-draft.md split into its header fields and body
+type LinearDraft struct {
+	Title       string
+	Team        string
+	Priority    string
+	Labels      []string
+	Description string // Everything after the header, markdown
+}
+
+var linearPriorities = []string{"Urgent", "High", "Medium", "Low", "No priority"}
+
+// Draft form fields, in tab order
+const (
+	draftFieldTitle = iota
+	draftFieldTeam
+	draftFieldPriority
+	draftFieldLabels
+	draftFieldDescription
+	draftFieldCount
+)
+
+var draftHeaderLine = regexp.MustCompile(`^\*\*(Title|Team|Priority|Labels):\*\*\s*(.*)$`)
+
+// parseLinearDraft reads the **Field:** header lines at the top of the draft.
+// The first line that isn't a header (after skipping blanks) starts the
+// description.
+func parseLinearDraft(content string) *LinearDraft {
+	d := &LinearDraft{}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	i := 0
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		match := draftHeaderLine.FindStringSubmatch(line)
+		if match == nil {
+			break
+		}
+		value := strings.TrimSpace(match[2])
+		switch match[1] {
+		case "Title":
+			d.Title = value
+		case "Team":
+			d.Team = value
+		case "Priority":
+			d.Priority = value
+		case "Labels":
+			d.Labels = splitLabels(value)
+		}
+	}
+	d.Description = strings.TrimSpace(strings.Join(lines[i:], "\n"))
+	return d
+}
+
+func splitLabels(s string) []string {
+	var labels []string
+	for _, l := range strings.Split(s, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+// Markdown writes the draft back in the format investigations produce
+func (d *LinearDraft) Markdown() string {
+	return fmt.Sprintf("**Title:** %s\n**Team:** %s\n**Priority:** %s\n**Labels:** %s\n\n%s\n",
+		d.Title, d.Team, d.Priority, strings.Join(d.Labels, ", "), d.Description)
+}
+
+// validate returns the problems that block filing, keyed by form field
+func (d *LinearDraft) validate() map[int]string {
+	problems := make(map[int]string)
+	switch {
+	case strings.TrimSpace(d.Title) == "":
+		problems[draftFieldTitle] = "title is required"
+	case len(d.Title) > 255:
+		problems[draftFieldTitle] = "title must be 255 characters or fewer"
+	}
+	if strings.TrimSpace(d.Team) == "" {
+		problems[draftFieldTeam] = "team is required"
+	}
+	if priorityIndex(d.Priority) < 0 {
+		problems[draftFieldPriority] = "priority must be one of " + strings.Join(linearPriorities, ", ")
+	}
+	if strings.TrimSpace(d.Description) == "" {
+		problems[draftFieldDescription] = "description is required"
+	}
+	return problems
+}
+
+func priorityIndex(p string) int {
+	for i, name := range linearPriorities {
+		if strings.EqualFold(name, strings.TrimSpace(p)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func newLinearDraftInputs() (textinput.Model, textinput.Model, textinput.Model, textarea.Model) {
+	title := textinput.New()
+	title.Placeholder = "Issue title"
+	title.CharLimit = 255
+
+	team := textinput.New()
+	team.Placeholder = "Team name"
+
+	labels := textinput.New()
+	labels.Placeholder = "comma, separated, labels"
+
+	desc := textarea.New()
+	desc.Placeholder = "Description (markdown)"
+	desc.CharLimit = 0
+	return title, team, labels, desc
+}
+
+// fillDraftForm loads a draft into the form controls
+func (m *model) fillDraftForm(investigationID int, d *LinearDraft) {
+	m.draftFormFor = investigationID
+	m.draftDirty = false
+	m.draftProblems = nil
+	if d == nil {
+		d = &LinearDraft{}
+	}
+	m.draftTitleInput.SetValue(d.Title)
+	m.draftTeamInput.SetValue(d.Team)
+	m.draftLabelsInput.SetValue(strings.Join(d.Labels, ", "))
+	m.draftTitleInput.CursorStart()
+	m.draftTeamInput.CursorStart()
+	m.draftLabelsInput.CursorStart()
+	m.draftDescArea.SetValue(d.Description)
+	m.draftPriority = priorityIndex(d.Priority)
+	m.draftUnknownPriority = ""
+	if m.draftPriority < 0 && d.Priority != "" {
+		// Keep what the agent wrote so validation can point at it
+		m.draftUnknownPriority = d.Priority
+	}
+}
+
+// sizeDraftForm fits the inputs to the tab content width. Values are set
+// again so the inputs recompute their scroll offsets.
+func (m *model) sizeDraftForm(contentWidth int) {
+	// Panel border and padding, label column, prompt and cursor
+	inputWidth := contentWidth - 8 - 13 - 3
+	if inputWidth < 20 {
+		inputWidth = 20
+	}
+	for _, input := range []*textinput.Model{&m.draftTitleInput, &m.draftTeamInput, &m.draftLabelsInput} {
+		input.Width = inputWidth
+		input.SetValue(input.Value())
+		if !input.Focused() {
+			input.CursorStart()
+		}
+	}
+}
+
+// formDraft builds a draft from the current form values
+func (m model) formDraft() *LinearDraft {
+	priority := m.draftUnknownPriority
+	if m.draftPriority >= 0 {
+		priority = linearPriorities[m.draftPriority]
+	}
+	return &LinearDraft{
+		Title:       strings.TrimSpace(m.draftTitleInput.Value()),
+		Team:        strings.TrimSpace(m.draftTeamInput.Value()),
+		Priority:    priority,
+		Labels:      splitLabels(m.draftLabelsInput.Value()),
+		Description: strings.TrimSpace(m.draftDescArea.Value()),
+	}
+}
+
+func (m *model) focusDraftField(field int) {
+	m.draftFocus = field
+	m.draftTitleInput.Blur()
+	m.draftTeamInput.Blur()
+	m.draftLabelsInput.Blur()
+	m.draftDescArea.Blur()
+	switch field {
+	case draftFieldTitle:
+		m.draftTitleInput.Focus()
+	case draftFieldTeam:
+		m.draftTeamInput.Focus()
+	case draftFieldLabels:
+		m.draftLabelsInput.Focus()
+	case draftFieldDescription:
+		m.draftDescArea.Focus()
+	}
+}
+
+// startDraftEdit enters the form; e on the Linear Draft tab
+func (m *model) startDraftEdit() {
+	m.editingDraft = true
+	m.focusDraftField(draftFieldTitle)
+}
+
+func (m *model) stopDraftEdit() {
+	m.editingDraft = false
+	m.focusDraftField(-1)
+}
+
+// handleLinearDraftKey handles keys while the draft form has focus
+func (m model) handleLinearDraftKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	inv := m.getSelectedInvestigation()
+	if inv == nil {
+		m.stopDraftEdit()
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, keys.Escape):
+		if m.draftDirty {
+			m.pushModal(modalConfirm)
+			m.confirmAction = "discard_draft"
+			m.confirmMessage = "Discard unsaved changes to the Linear draft?"
+			return m, nil
+		}
+		m.stopDraftEdit()
+		return m, nil
+
+	case key.Matches(msg, keys.Save):
+		draft := m.formDraft()
+		m.draftProblems = draft.validate()
+		return m, saveLinearDraftCmd(inv.ID, draft, m.expectedDiskState(docKey{inv.ID, "linear-draft.md"}))
+
+	case msg.String() == "tab":
+		m.focusDraftField((m.draftFocus + 1) % draftFieldCount)
+		return m, nil
+
+	case msg.String() == "shift+tab":
+		m.focusDraftField((m.draftFocus + draftFieldCount - 1) % draftFieldCount)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	before := m.formDraft().Markdown()
+	switch m.draftFocus {
+	case draftFieldTitle:
+		m.draftTitleInput, cmd = m.draftTitleInput.Update(msg)
+	case draftFieldTeam:
+		m.draftTeamInput, cmd = m.draftTeamInput.Update(msg)
+	case draftFieldPriority:
+		switch msg.String() {
+		case "left", "h":
+			if m.draftPriority < 0 {
+				m.draftPriority = 0
+			} else {
+				m.draftPriority = (m.draftPriority + len(linearPriorities) - 1) % len(linearPriorities)
+			}
+		case "right", "l", " ":
+			m.draftPriority = (m.draftPriority + 1) % len(linearPriorities)
+		}
+	case draftFieldLabels:
+		m.draftLabelsInput, cmd = m.draftLabelsInput.Update(msg)
+	case draftFieldDescription:
+		m.draftDescArea, cmd = m.draftDescArea.Update(msg)
+	}
+	if m.formDraft().Markdown() != before {
+		m.draftDirty = true
+		if m.draftProblems != nil {
+			m.draftProblems = m.formDraft().validate()
+		}
+	}
+	return m, cmd
+}
+
+// fileLinearDraft validates the saved draft and sends it through the safety
+// gate. What gets filed is linear-draft.md as it is on disk, so unsaved form
+// edits have to be saved first.
+func (m *model) fileLinearDraft() tea.Cmd {
+	inv := m.getSelectedInvestigation()
+	if inv == nil || m.draftFormFor != inv.ID {
+		return nil
+	}
+	if inv.LinearIssueID != "" {
+		return m.notify(severityWarning, "file Linear issue", fmt.Sprintf("#%d is already filed as %s", inv.ID, inv.LinearIssueID), nil)
+	}
+	if m.draftDirty {
+		return m.notify(severityWarning, "file Linear issue", "Save the draft (ctrl+s) before filing", nil)
+	}
+	draft := m.linearDrafts[inv.ID]
+	if draft == nil {
+		return m.notify(severityWarning, "file Linear issue", fmt.Sprintf("#%d has no saved linear-draft.md", inv.ID), nil)
+	}
+
+	m.draftProblems = draft.validate()
+	if len(m.draftProblems) > 0 {
+		return m.notify(severityWarning, "file Linear issue", fmt.Sprintf("Fix %d field(s) before filing", len(m.draftProblems)), nil)
+	}
+
+	preview := fmt.Sprintf("%s\nTeam: %s • Priority: %s • Labels: %s\n\n%s",
+		draft.Title, draft.Team, draft.Priority, strings.Join(draft.Labels, ", "), draft.Description)
+	return m.requestOutward(outwardRequest{
+		kind:            outwardLinear,
+		investigationID: inv.ID,
+		title:           fmt.Sprintf("File Linear issue for #%d", inv.ID),
+		preview:         preview,
+		busyLabel:       "Filing Linear issue...",
+		detail:          "team=" + draft.Team,
+		run:             fileLinearIssueCmd(inv.ID, draft, m.expectedDiskState(docKey{inv.ID, "linear-draft.md"})),
+	})
+}
+
+// Load linear-draft.md
+func loadLinearDraftCmd(investigationID, gen int) tea.Cmd {
+	return func() tea.Msg {
+		path := resolveInvestigationFile(investigationID, "linear-draft.md")
+		if path == "" {
+			return linearDraftLoadedMsg{investigationID: investigationID, gen: gen}
+		}
+		disk, err := readDiskState(path)
+		if err != nil {
+			return errMsg{err: err, source: "load Linear draft", retry: reloadCmd(loadLinearDraft, investigationID, "")}
+		}
+		return linearDraftLoadedMsg{investigationID: investigationID, draft: parseLinearDraft(disk.Content), disk: disk, gen: gen}
+	}
+}
+
+// Save the edited draft back to linear-draft.md, unless it changed on disk
+// since the form was filled
+func saveLinearDraftCmd(investigationID int, draft *LinearDraft, expected *diskState) tea.Cmd {
+	return func() tea.Msg {
+		path := resolveInvestigationFile(investigationID, "linear-draft.md")
+		if path == "" {
+			path = fmt.Sprintf("%s/%d/linear-draft.md", investigationsDir, investigationID)
+		}
+		disk, conflict, err := writeChecked(path, draft.Markdown(), expected)
+		if err != nil {
+			return linearDraftSavedMsg{investigationID: investigationID, err: err}
+		}
+		if conflict != nil {
+			conflict.key = docKey{investigationID, "linear-draft.md"}
+			conflict.save = func(expected *diskState) tea.Cmd {
+				return saveLinearDraftCmd(investigationID, draft, expected)
+			}
+			return saveConflictMsg{conflict: conflict}
+		}
+		return linearDraftSavedMsg{investigationID: investigationID, draft: draft, disk: disk}
+	}
+}
+
+// File the draft as a Linear issue through the Express API. The draft is
+// what was reviewed; if linear-draft.md changed since, nothing is filed.
+func fileLinearIssueCmd(investigationID int, draft *LinearDraft, expected *diskState) tea.Cmd {
+	return func() tea.Msg {
+		if expected != nil {
+			disk, err := readDiskState(resolveInvestigationFile(investigationID, "linear-draft.md"))
+			if err != nil {
+				return linearIssueFiledMsg{investigationID: investigationID, err: err}
+			}
+			if !disk.sameContent(*expected) {
+				return linearIssueFiledMsg{investigationID: investigationID,
+					err: fmt.Errorf("linear-draft.md changed on disk since it was loaded; reload it and review before filing")}
+			}
+		}
+
+		path := fmt.Sprintf("/api/investigations/%d/linear-issue", investigationID)
+		body := map[string]interface{}{
+			"title":       draft.Title,
+			"team":        draft.Team,
+			"priority":    draft.Priority,
+			"labels":      draft.Labels,
+			"description": draft.Description,
+		}
+		var result struct {
+			Identifier string `json:"identifier"`
+			URL        string `json:"url"`
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		defer cancel()
+		if err := api.post(ctx, path, body, &result); err != nil {
+			if endpointMissing(err) {
+				err = fmt.Errorf("server has no %s endpoint; update the UI server", path)
+			}
+			return linearIssueFiledMsg{investigationID: investigationID, err: err}
+		}
+		if result.Identifier == "" {
+			return linearIssueFiledMsg{investigationID: investigationID,
+				err: fmt.Errorf("server returned no issue identifier; check Linear before filing again")}
+		}
+		return linearIssueFiledMsg{investigationID: investigationID, identifier: result.Identifier, url: result.URL}
+	}
+}
+
+// Record the filed issue on the investigation so it isn't filed twice
+func recordLinearIssueCmd(investigationID int, identifier string) tea.Cmd {
+	return func() tea.Msg {
+		path := fmt.Sprintf("/api/investigations/%d", investigationID)
+		body := map[string]string{"linear_issue_id": identifier}
+		if err := api.put(context.Background(), path, body, nil); err != nil {
+			return errMsg{
+				err:    fmt.Errorf("filed %s but couldn't record it on the investigation: %w", identifier, err),
+				source: "record Linear issue",
+				retry:  recordLinearIssueCmd(investigationID, identifier),
+			}
+		}
+		return nil
+	}
+}
+
+func (msg linearIssueFiledMsg) outwardErr() error  { return msg.err }
+func (msg linearIssueFiledMsg) outwardRef() string { return msg.identifier }
+
+// linearDraftTabStatus marks the tab done once the issue is filed
+func linearDraftTabStatus(inv *Investigation) string {
+	if inv.LinearIssueID != "" {
+		return "complete"
+	}
+	return ""
+}
+
+func (m model) renderLinearDraftView(width, height int) string {
+	inv := m.getSelectedInvestigation()
+	if inv == nil {
+		return ""
+	}
+	panel := contentPanelStyle.Width(width - 4).Height(height - 2)
+
+	if m.draftFormFor != inv.ID {
+		return panel.Render(fmt.Sprintf("%s Loading Linear draft...", m.spinner.View()))
+	}
+	if m.linearDrafts[inv.ID] == nil && !m.editingDraft {
+		return panel.Render(emptyStateStyle.Render(
+			"No linear-draft.md for this investigation.\n\nDrafts are written for bug investigations. Press [e] to write one."))
+	}
+
+	header := "LINEAR DRAFT"
+	if inv.LinearIssueID != "" {
+		header += "  " + lipgloss.NewStyle().Foreground(statusCompleted).Render(withIcon(glyphDone, "Filed as "+inv.LinearIssueID))
+	} else if m.draftDirty {
+		header += "  " + dimmedTextStyle.Render("(unsaved changes)")
+	}
+
+	labelStyle := lipgloss.NewStyle().Width(12).Foreground(textSecondary)
+	field := func(idx int, label, control string) string {
+		l := labelStyle.Render(label)
+		if m.editingDraft && m.draftFocus == idx {
+			l = labelStyle.Foreground(c1Primary).Bold(true).Render(label)
+		}
+		row := l + " " + control
+		if problem, ok := m.draftProblems[idx]; ok {
+			row += "\n" + strings.Repeat(" ", 13) + lipgloss.NewStyle().Foreground(statusError).Render(problem)
+		}
+		return row
+	}
+
+	var priorities []string
+	for i, p := range linearPriorities {
+		if i == m.draftPriority {
+			priorities = append(priorities, selectedItemStyle.UnsetPadding().Render("["+p+"]"))
+		} else {
+			priorities = append(priorities, dimmedTextStyle.Render(" "+p+" "))
+		}
+	}
+	priorityControl := strings.Join(priorities, " ")
+	if m.draftUnknownPriority != "" && m.draftPriority < 0 {
+		priorityControl += dimmedTextStyle.Render("  (draft says " + m.draftUnknownPriority + ")")
+	}
+
+	form := strings.Join([]string{
+		field(draftFieldTitle, "Title", m.draftTitleInput.View()),
+		field(draftFieldTeam, "Team", m.draftTeamInput.View()),
+		field(draftFieldPriority, "Priority", priorityControl),
+		field(draftFieldLabels, "Labels", m.draftLabelsInput.View()),
+		field(draftFieldDescription, "Description", ""),
+	}, "\n")
+
+	descHeight := height - 2 - 2 - lipgloss.Height(form) - 3
+	if descHeight < 3 {
+		descHeight = 3
+	}
+	var description string
+	if m.editingDraft {
+		m.draftDescArea.SetWidth(width - 10)
+		m.draftDescArea.SetHeight(descHeight)
+		description = m.draftDescArea.View()
+	} else {
+		description = lipgloss.NewStyle().
+			Foreground(textPrimary).
+			Width(width - 10).
+			Height(descHeight).
+			MaxHeight(descHeight).
+			Render(m.draftDescArea.Value())
+	}
+
+	var footer string
+	switch {
+	case m.editingDraft:
+		footer = "Tab: next field • ←→: priority • Ctrl+S: save • Esc: done"
+	case inv.LinearIssueID != "":
+		footer = "[e/E] Edit • [c] Copy • [v] Select to copy"
+	default:
+		footer = "[e/E] Edit • [c] Copy • [p] File in Linear • [v] Select to copy"
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		sectionHeaderStyle.Render(header),
+		form,
+		description,
+		dimmedTextStyle.Render(footer),
+	)
+	return panel.Render(content)
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// lintFinding is one problem with a customer response. Errors block posting;
+// warnings are advice.
+type lintFinding struct {
+	Severity severity
+	Rule     string // e.g. "internal-ref"
+	Line     int    // 1-based; 0 when the finding is about the whole response
+	Message  string
+}
+
+// Internal-only references that must never reach a customer
+var (
+	// Linear issue IDs such as ENG-1234
+	linearIDPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]{1,9}-\d{1,6}\b`)
+	// Slack channels such as #eng-oncall; ticket numbers like #8314 don't
+	// start with a letter and markdown headers have a space after the #
+	slackChannelPattern = regexp.MustCompile(`(?:^|[\s(])(#[a-z][a-z0-9_-]*)`)
+	internalURLPattern  = regexp.MustCompile(`(?i)\b(?:https?://)?(?:[a-z0-9-]+\.)*(?:linear\.app|slack\.com|notion\.so|notion\.site)\b[^\s)>\]]*` +
+		`|\bhttps?://(?:localhost|127\.0\.0\.1|10(?:\.\d{1,3}){3}|192\.168(?:\.\d{1,3}){2}|[a-z0-9.-]+\.(?:internal|local|corp))\b[^\s)>\]]*`)
+	agentNamePattern = regexp.MustCompile(`(?i)\b(?:slack|linear|pylon|codebase|triage|investigation|research)[ -]agents?\b|\bsub-?agents?\b`)
+)
+
+// Prefixes that look like Linear IDs but are standards, encodings and CVEs
+var linearIDExceptions = map[string]bool{
+	"AES": true, "CVE": true, "HTTP": true, "ISO": true, "MD": true,
+	"RFC": true, "RSA": true, "SHA": true, "TLS": true, "UTF": true,
+}
+
+// Template text nobody filled in. Single braces only count around the usual
+// placeholder words, since JSON and config snippets are normal in replies.
+var placeholderPattern = regexp.MustCompile(`\{\{[^{}\n]{1,100}\}\}` +
+	`|(?i:\{(?:customer(?:[ _]name)?|(?:first[ _]|last[ _])?name|company|ticket(?:[ _](?:id|number))?|date|link|url|insert[^{}\n]*|todo|tbd)\})` +
+	`|(?i:\[(?:todo|tbd|insert[^\]\n]*|placeholder[^\]\n]*|customer(?: name)?|name)\])` +
+	`|\b(?:TODO|TBD|FIXME|XXX)\b` +
+	`|(?i:<(?:customer|your|insert|name)[^<>\n]*>)`)
+
+// A lone {word} may be a placeholder too, but is only worth a warning
+var bracePlaceholderPattern = regexp.MustCompile(`\{[A-Za-z][A-Za-z0-9_ -]{0,40}\}`)
+
+// lintResponse checks a customer response against the house style rules
+// and the checks every response gets. Findings are ordered by line.
+func lintResponse(content string, style ResponseStyleSettings) []lintFinding {
+	var findings []lintFinding
+	add := func(sev severity, rule string, offset int, format string, args ...interface{}) {
+		line := 0
+		if offset >= 0 {
+			line = strings.Count(content[:offset], "\n") + 1
+		}
+		findings = append(findings, lintFinding{Severity: sev, Rule: rule, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Compare with whitespace collapsed so line wrapping doesn't matter
+	normalized := strings.ToLower(strings.Join(strings.Fields(content), " "))
+	for _, phrase := range style.AlwaysInclude {
+		want := strings.ToLower(strings.Join(strings.Fields(phrase), " "))
+		if want != "" && !strings.Contains(normalized, want) {
+			add(severityError, "always-include", -1, "Missing required phrase %q", phrase)
+		}
+	}
+
+	lower := strings.ToLower(content)
+	for _, rule := range style.NeverDo {
+		rule = strings.TrimSpace(rule)
+		if len(rule) > 2 && strings.HasPrefix(rule, "/") && strings.HasSuffix(rule, "/") {
+			re, err := regexp.Compile("(?i)" + rule[1:len(rule)-1])
+			if err != nil {
+				add(severityWarning, "never-do", -1, "never_do pattern %s is not a valid regex", rule)
+				continue
+			}
+			if loc := re.FindStringIndex(content); loc != nil {
+				add(severityError, "never-do", loc[0], "Matches forbidden pattern %s: %q", rule, content[loc[0]:loc[1]])
+			}
+			continue
+		}
+		if rule == "" {
+			continue
+		}
+		if i := strings.Index(lower, strings.ToLower(rule)); i >= 0 {
+			add(severityError, "never-do", i, "Contains forbidden phrase %q", rule)
+		}
+	}
+
+	// IDs and channels inside an internal link are reported with the link
+	links := internalURLPattern.FindAllStringIndex(content, -1)
+	inLink := func(offset int) bool {
+		for _, loc := range links {
+			if offset >= loc[0] && offset < loc[1] {
+				return true
+			}
+		}
+		return false
+	}
+	for _, loc := range links {
+		add(severityError, "internal-ref", loc[0], "Internal link %s", truncateStr(content[loc[0]:loc[1]], 60))
+	}
+	for _, loc := range linearIDPattern.FindAllStringIndex(content, -1) {
+		id := content[loc[0]:loc[1]]
+		if linearIDExceptions[id[:strings.IndexByte(id, '-')]] || inLink(loc[0]) {
+			continue
+		}
+		add(severityError, "internal-ref", loc[0], "Linear issue ID %s is internal", id)
+	}
+	for _, loc := range slackChannelPattern.FindAllStringSubmatchIndex(content, -1) {
+		if inLink(loc[2]) {
+			continue
+		}
+		add(severityError, "internal-ref", loc[2], "Slack channel %s is internal", content[loc[2]:loc[3]])
+	}
+	for _, loc := range agentNamePattern.FindAllStringIndex(content, -1) {
+		add(severityError, "internal-ref", loc[0], "Mentions internal agent %q", content[loc[0]:loc[1]])
+	}
+
+	placeholders := placeholderPattern.FindAllStringIndex(content, -1)
+	for _, loc := range placeholders {
+		add(severityError, "placeholder", loc[0], "Unfilled placeholder %s", truncateStr(content[loc[0]:loc[1]], 40))
+	}
+	reported := func(loc []int) bool {
+		for _, p := range placeholders {
+			if loc[0] < p[1] && p[0] < loc[1] {
+				return true
+			}
+		}
+		return false
+	}
+	for _, loc := range bracePlaceholderPattern.FindAllStringIndex(content, -1) {
+		if !reported(loc) {
+			add(severityWarning, "placeholder", loc[0], "Possible placeholder %s", content[loc[0]:loc[1]])
+		}
+	}
+
+	maxWords := style.MaxParagraphWords
+	if maxWords <= 0 {
+		maxWords = defaultMaxParagraphWords
+	}
+	offset, start, words := 0, -1, 0
+	flush := func() {
+		if start >= 0 && words > maxWords {
+			add(severityWarning, "long-paragraph", start, "Paragraph is %d words; keep it under %d", words, maxWords)
+		}
+		start, words = -1, 0
+	}
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			flush()
+		} else {
+			if start < 0 {
+				start = offset
+			}
+			words += len(strings.Fields(line))
+		}
+		offset += len(line)
+	}
+	flush()
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Line < findings[j].Line })
+	return findings
+}
+
+// lintCounts tallies findings by severity
+func lintCounts(findings []lintFinding) (errors, warnings int) {
+	for _, f := range findings {
+		switch f.Severity {
+		case severityError:
+			errors++
+		case severityWarning:
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+// firstLintError is the first finding that blocks posting, if any
+func firstLintError(findings []lintFinding) *lintFinding {
+	for i := range findings {
+		if findings[i].Severity == severityError {
+			return &findings[i]
+		}
+	}
+	return nil
+}
+
+// lintSavedResponse re-lints an investigation's response as loaded or saved
+func (m *model) lintSavedResponse(investigationID int) {
+	if response := m.customerResponses[investigationID]; response != nil {
+		m.responseLints[investigationID] = lintResponse(response.Content, m.settings.responseStyle())
+	} else {
+		delete(m.responseLints, investigationID)
+	}
+}
+
+// lintEditedResponse lints the response editor's text as typed
+func (m *model) lintEditedResponse() {
+	m.editLint = lintResponse(m.responseTextarea.Value(), m.settings.responseStyle())
+}
+
+// relintResponses re-checks everything after the style rules change
+func (m *model) relintResponses() {
+	for id := range m.customerResponses {
+		m.lintSavedResponse(id)
+	}
+	if m.editingResponse {
+		m.lintEditedResponse()
+	}
+}
+
+// renderLint shows the findings in at most maxLines lines, the summary first.
+// It is empty when there is nothing to report.
+func renderLint(findings []lintFinding, width, maxLines int) string {
+	if len(findings) == 0 || maxLines < 1 {
+		return ""
+	}
+	errs, warns := lintCounts(findings)
+	var counts []string
+	for _, c := range []struct {
+		n    int
+		noun string
+	}{{errs, "error"}, {warns, "warning"}} {
+		switch {
+		case c.n == 1:
+			counts = append(counts, "1 "+c.noun)
+		case c.n > 1:
+			counts = append(counts, fmt.Sprintf("%d %ss", c.n, c.noun))
+		}
+	}
+	summary := "Style check: " + strings.Join(counts, ", ")
+	color := statusRunning
+	if errs > 0 {
+		summary += " • posting blocked"
+		color = statusError
+	}
+	lines := []string{lipgloss.NewStyle().Bold(true).Foreground(color).Render(truncateStr(summary, width))}
+
+	for k, f := range findings {
+		if len(lines) == maxLines-1 && len(findings)-k > 1 {
+			lines = append(lines, dimmedTextStyle.Render(fmt.Sprintf("  … %d more", len(findings)-k)))
+			break
+		}
+		if len(lines) == maxLines {
+			break
+		}
+		where := "  "
+		if f.Line > 0 {
+			where = fmt.Sprintf("  L%d ", f.Line)
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(f.Severity.color()).
+			Render(truncateStr(where+f.Message, width)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lintHeight is how many lines renderLint needs, capped at maxLines
+func lintHeight(findings []lintFinding, maxLines int) int {
+	if len(findings) == 0 {
+		return 0
+	}
+	if n := len(findings) + 1; n < maxLines {
+		return n
+	}
+	return maxLines
+}
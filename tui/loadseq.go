@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// loadKind names a kind of background load. Together with the investigation
+// and agent it identifies a slot whose latest result wins.
+type loadKind int
+
+const (
+	loadInvestigations loadKind = iota
+	loadAgentStatuses
+	loadAgentLogs
+	loadAgentFindings
+	loadTicketData
+	loadPhase1Findings
+	loadSummary
+	loadCustomerResponse
+	loadLinearDraft
+	loadKBArticle
+	loadVersions
+)
+
+type loadKey struct {
+	kind            loadKind
+	investigationID int
+	agent           string // Lowercased; empty for non-agent loads
+}
+
+// loadTracker hands out generations and owns the context that loads for the
+// selected investigation run under. Both the API calls and the polled file
+// reads take it.
+type loadTracker struct {
+	seq     int
+	applied map[loadKey]int // Generation of the newest result applied per key
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func newLoadTracker() *loadTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &loadTracker{applied: make(map[loadKey]int), ctx: ctx, cancel: cancel}
+}
+
+// next stamps a new load. Generations come from one counter, so they order
+// loads for the same key by when they were issued.
+func (t *loadTracker) next(key loadKey) int {
+	t.seq++
+	return t.seq
+}
+
+// cancelInFlight aborts API requests issued for the previous selection
+func (t *loadTracker) cancelInFlight() {
+	t.cancel()
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+}
+
+// isStale reports whether a result is older than one already applied for the
+// same key, i.e. it arrived out of order; otherwise it records the result as
+// applied. Issuing a newer load doesn't make a result stale: the tick reissues
+// loads every 2s, and a load slower than that must still land.
+func (m model) isStale(key loadKey, gen int) bool {
+	if gen < m.loads.applied[key] {
+		return true
+	}
+	m.loads.applied[key] = gen
+	return false
+}
+
+func agentKey(kind loadKind, investigationID int, agentName string) loadKey {
+	return loadKey{kind: kind, investigationID: investigationID, agent: strings.ToLower(agentName)}
+}
+
+// reloadCmd is the retry for a failed load. Reusing the failed load's
+// generation could let the retry overwrite newer results, so the retry goes
+// back through Update and reload stamps a new one when it runs.
+func reloadCmd(kind loadKind, investigationID int, agentName string) tea.Cmd {
+	return func() tea.Msg {
+		return reloadMsg{kind: kind, investigationID: investigationID, agentName: agentName}
+	}
+}
+
+// reload issues the load a reloadMsg asks for
+func (m model) reload(msg reloadMsg) tea.Cmd {
+	switch msg.kind {
+	case loadInvestigations:
+		return m.investigationsCmd()
+	case loadAgentStatuses:
+		return m.agentStatusesCmd(msg.investigationID)
+	case loadAgentLogs:
+		return m.agentLogsCmd(msg.investigationID, msg.agentName)
+	case loadAgentFindings:
+		return m.agentFindingsCmd(msg.investigationID, msg.agentName)
+	case loadTicketData:
+		return m.ticketDataCmd(msg.investigationID)
+	case loadPhase1Findings:
+		return m.phase1FindingsCmd(msg.investigationID)
+	case loadSummary:
+		return m.summaryCmd(msg.investigationID)
+	case loadCustomerResponse:
+		return m.customerResponseCmd(msg.investigationID)
+	case loadLinearDraft:
+		return m.linearDraftCmd(msg.investigationID)
+	case loadKBArticle:
+		return m.kbArticleCmd(msg.investigationID)
+	case loadVersions:
+		return m.versionsCmd(msg.investigationID)
+	}
+	return nil
+}
+
+// The helpers below issue loads through the tracker; Update should call these
+// instead of the raw commands so every reply can be checked with isStale.
+
+func (m model) investigationsCmd() tea.Cmd {
+	return loadInvestigationsCmd(m.loads.next(loadKey{kind: loadInvestigations}))
+}
+
+func (m model) agentStatusesCmd(investigationID int) tea.Cmd {
+	gen := m.loads.next(loadKey{kind: loadAgentStatuses, investigationID: investigationID})
+	return loadAgentStatusesCmd(m.loads.ctx, investigationID, gen)
+}
+
+func (m model) agentLogsCmd(investigationID int, agentName string) tea.Cmd {
+	gen := m.loads.next(agentKey(loadAgentLogs, investigationID, agentName))
+	return streamAgentLogsCmd(m.loads.ctx, investigationID, agentName, gen)
+}
+
+func (m model) agentFindingsCmd(investigationID int, agentName string) tea.Cmd {
+	gen := m.loads.next(agentKey(loadAgentFindings, investigationID, agentName))
+	return loadAgentFindingsCmd(m.loads.ctx, investigationID, agentName, gen)
+}
+
+func (m model) ticketDataCmd(investigationID int) tea.Cmd {
+	gen := m.loads.next(loadKey{kind: loadTicketData, investigationID: investigationID})
+	return loadTicketDataCmd(m.loads.ctx, investigationID, gen)
+}
+
+func (m model) phase1FindingsCmd(investigationID int) tea.Cmd {
+	gen := m.loads.next(loadKey{kind: loadPhase1Findings, investigationID: investigationID})
+	return loadPhase1FindingsCmd(m.loads.ctx, investigationID, gen)
+}
+
+func (m model) summaryCmd(investigationID int) tea.Cmd {
+	gen := m.loads.next(loadKey{kind: loadSummary, investigationID: investigationID})
+	return loadSummaryCmd(m.loads.ctx, investigationID, gen)
+}
+
+func (m model) customerResponseCmd(investigationID int) tea.Cmd {
+	gen := m.loads.next(loadKey{kind: loadCustomerResponse, investigationID: investigationID})
+	return loadCustomerResponseCmd(investigationID, gen)
+}
+
+func (m model) linearDraftCmd(investigationID int) tea.Cmd {
+	gen := m.loads.next(loadKey{kind: loadLinearDraft, investigationID: investigationID})
+	return loadLinearDraftCmd(investigationID, gen)
+}
+
+func (m model) kbArticleCmd(investigationID int) tea.Cmd {
+	gen := m.loads.next(loadKey{kind: loadKBArticle, investigationID: investigationID})
+	return loadKBArticleCmd(m.loads.ctx, investigationID, gen)
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -35,10 +37,12 @@ var keys = struct {
 	Tab4     key.Binding
 	Tab5     key.Binding
 	Tab6     key.Binding
+	Tab7     key.Binding
 	TabNext  key.Binding
 	PageUp   key.Binding
 	PageDown key.Binding
 	Edit     key.Binding
+	EditExternal key.Binding
 	Copy     key.Binding
 	Post     key.Binding
 	Save     key.Binding
@@ -49,6 +53,20 @@ var keys = struct {
 	Debug    key.Binding
 	New      key.Binding
 	Reset    key.Binding
+	Errors   key.Binding
+	Select   key.Binding
+	Export   key.Binding
+	Generate key.Binding
+	Metrics  key.Binding
+	Settings key.Binding
+	Retry    key.Binding
+	StopAgent   key.Binding
+	RetryAgent  key.Binding
+	RetryFailed key.Binding
+	History     key.Binding
+	Snippets    key.Binding
+	Insights    key.Binding
+	Palette     key.Binding
 }{
 	Quit:     key.NewBinding(key.WithKeys("q", "ctrl+c")),
 	Up:       key.NewBinding(key.WithKeys("up", "k")),
@@ -62,10 +80,12 @@ var keys = struct {
 	Tab4:     key.NewBinding(key.WithKeys("4")),
 	Tab5:     key.NewBinding(key.WithKeys("5")),
 	Tab6:     key.NewBinding(key.WithKeys("6")),
+	Tab7:     key.NewBinding(key.WithKeys("7")),
 	TabNext:  key.NewBinding(key.WithKeys("tab")),
 	PageUp:   key.NewBinding(key.WithKeys("pgup")),
 	PageDown: key.NewBinding(key.WithKeys("pgdown")),
 	Edit:     key.NewBinding(key.WithKeys("e")),
+	EditExternal: key.NewBinding(key.WithKeys("E")),
 	Copy:     key.NewBinding(key.WithKeys("c")),
 	Post:     key.NewBinding(key.WithKeys("p")),
 	Save:     key.NewBinding(key.WithKeys("ctrl+s")),
@@ -76,11 +96,25 @@ var keys = struct {
 	Debug:    key.NewBinding(key.WithKeys("?")),
 	New:      key.NewBinding(key.WithKeys("n")),
 	Reset:    key.NewBinding(key.WithKeys("R")),
+	Errors:   key.NewBinding(key.WithKeys("!")),
+	Select:   key.NewBinding(key.WithKeys("v")),
+	Export:   key.NewBinding(key.WithKeys("x")),
+	Generate: key.NewBinding(key.WithKeys("g")),
+	Metrics:  key.NewBinding(key.WithKeys("M")),
+	Settings: key.NewBinding(key.WithKeys("S")),
+	Retry:    key.NewBinding(key.WithKeys("ctrl+r")),
+	StopAgent:   key.NewBinding(key.WithKeys("s")),
+	RetryAgent:  key.NewBinding(key.WithKeys("t")),
+	RetryFailed: key.NewBinding(key.WithKeys("T")),
+	History:     key.NewBinding(key.WithKeys("h")),
+	Snippets:    key.NewBinding(key.WithKeys("ctrl+o")),
+	Insights:    key.NewBinding(key.WithKeys("I")),
+	Palette:     key.NewBinding(key.WithKeys("ctrl+k")),
 }
 
 func initialModel() model {
 	s := spinner.New()
-	s.Spinner = spinner.Dot
+	s.Spinner = spinnerFrames()
 	s.Style = spinnerStyle
 
 	ta := textarea.New()
@@ -99,16 +133,48 @@ func initialModel() model {
 	resetCtx.Placeholder = "Additional context for the new run (optional)..."
 	resetCtx.SetHeight(3)
 
+	agentRetryCtx := textarea.New()
+	agentRetryCtx.Placeholder = "Extra context for the agent (optional)..."
+	agentRetryCtx.SetHeight(3)
+
 	replyCtx := textarea.New()
 	replyCtx.Placeholder = "Additional context (optional)..."
 	replyCtx.SetHeight(3)
 
+	approvalTicket, approvalReviewer := newApprovalInputs()
+	draftTitle, draftTeam, draftLabels, draftDesc := newLinearDraftInputs()
+
+	kbArea := textarea.New()
+	kbArea.Placeholder = "KB article (markdown)..."
+	kbArea.CharLimit = 0
+
+	paletteQuery := textinput.New()
+	paletteQuery.Placeholder = "Type a command..."
+
+	snippetQuery := textinput.New()
+	snippetQuery.Placeholder = "Search snippets..."
+	snippetName := textinput.New()
+	snippetName.Placeholder = "e.g. Workaround offered"
+	snippetName.CharLimit = 60
+
 	return model{
 		agents:            make(map[int]map[string]*AgentState),
+		watchdog:          make(map[int]*investigationHealth),
+		procHistory:       make(map[int]*procHistory),
 		summaries:         make(map[int]*InvestigationSummary),
 		customerResponses: make(map[int]*CustomerResponse),
+		responseLints:     make(map[int][]lintFinding),
 		ticketData:        make(map[int]*TicketData),
 		phase1Findings:    make(map[int]string),
+		linearDrafts:      make(map[int]*LinearDraft),
+		kbArticles:        make(map[int]*KBArticle),
+		versions:          make(map[int][]InvestigationVersion),
+		diskStates:        make(map[docKey]diskState),
+		autosaved:         make(map[string]string),
+		generatedRecorded: make(map[string]bool),
+		failingLoads:      make(map[string]bool),
+		editBases:         make(map[docKey]diskState),
+		kbGenerating:      make(map[int]bool),
 		spinner:           s,
 		responseTextarea:  ta,
 		loading:           true,
@@ -116,15 +182,69 @@ func initialModel() model {
 		buildVersion:      buildVersion,
 		buildTime:         buildTime,
 		createTicketInput:  ti,
+		paletteQuery:       paletteQuery,
+		snippetQuery:       snippetQuery,
+		newSnippetName:     snippetName,
 		createContextArea:  ca,
 		resetContextArea:   resetCtx,
 		replyContextArea:   replyCtx,
+		agentRetryArea:     agentRetryCtx,
+		approvalTicketInput:   approvalTicket,
+		approvalReviewerInput: approvalReviewer,
+		draftTitleInput:       draftTitle,
+		draftTeamInput:        draftTeam,
+		draftLabelsInput:      draftLabels,
+		draftDescArea:         draftDesc,
+		draftPriority:         -1,
+		kbTextarea:            kbArea,
+		loads:              newLoadTracker(),
+	}
+}
+
+// moveSelection selects the investigation at index i in the sidebar
+func (m *model) moveSelection(i int) tea.Cmd {
+	m.selectedIndex = i
+	m.cp1Loaded = 0 // Reset so cp1 fields reload for new selection
+	m.kbScroll = 0
+	return m.selectInvestigation()
+}
+
+// selectInvestigation cancels loads still in flight for the previous
+// selection and returns the commands that load the new one.
+func (m model) selectInvestigation() tea.Cmd {
+	m.loads.cancelInFlight()
+
+	inv := m.getSelectedInvestigation()
+	if inv == nil {
+		return nil
+	}
+	cmds := []tea.Cmd{
+		m.agentStatusesCmd(inv.ID),
+		m.agentLogsCmd(inv.ID, m.getActiveAgentName()),
+		m.agentFindingsCmd(inv.ID, m.getActiveAgentName()),
+	}
+	// Load ticket data if at checkpoint 1
+	if inv.Status == "waiting" && inv.CurrentCheckpoint == "checkpoint_1_post_classification" {
+		cmds = append(cmds, m.ticketDataCmd(inv.ID))
+	}
+	// Load phase1 findings for investigations past checkpoint 1
+	if inv.Status == "complete" || inv.Status == "waiting" {
+		cmds = append(cmds, m.phase1FindingsCmd(inv.ID))
 	}
+	switch m.activeTab {
+	case TabLinearDraft:
+		cmds = append(cmds, m.linearDraftCmd(inv.ID))
+	case TabKB:
+		cmds = append(cmds, m.kbArticleCmd(inv.ID))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m model) Init() tea.Cmd {
 	return tea.Batch(
-		loadInvestigationsCmd(),
+		m.investigationsCmd(),
+		loadSettingsCmd(),
+		loadRecoveredDraftsCmd(draftsDir),
 		tickCmd(),     // Start periodic refresh
 		m.spinner.Tick, // Start spinner animation
 	)
@@ -159,9 +279,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.terminalViewport.Width = contentWidth - 8
 			m.terminalViewport.Height = terminalHeight - 4
 		}
+		m.sizeDraftForm(contentWidth)
 		return m, nil
 
 	case investigationsLoadedMsg:
+		if m.isStale(loadKey{kind: loadInvestigations}, msg.gen) {
+			return m, nil
+		}
+		delete(m.failingLoads, "load investigations")
 		wasLoading := m.loading
 		m.loading = false
 
@@ -169,71 +294,193 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !wasLoading && investigationsEqual(m.investigations, msg.investigations) {
 			return m, nil
 		}
+		var notifyCmd tea.Cmd
+		if !wasLoading {
+			if events := detectNotifyEvents(m.investigations, msg.investigations); len(events) > 0 {
+				notifyCmd = sendNotificationsCmd(notifyConfig, events)
+			}
+		}
 		m.investigations = msg.investigations
 
 		// Only trigger additional data loads on initial load, not tick refreshes
 		// (tick handler already loads agent data independently)
 		if wasLoading && len(m.investigations) > 0 && m.selectedIndex < len(m.investigations) {
-			inv := m.investigations[m.selectedIndex]
-			cmds := []tea.Cmd{
-				loadAgentStatusesCmd(inv.ID),
-				streamAgentLogsCmd(inv.ID, m.getActiveAgentName()),
-				loadAgentFindingsCmd(inv.ID, m.getActiveAgentName()),
-			}
-			// Load ticket data if at checkpoint 1
-			if inv.Status == "waiting" && inv.CurrentCheckpoint == "checkpoint_1_post_classification" {
-				cmds = append(cmds, loadTicketDataCmd(inv.ID))
-			}
-			// Load phase1 findings for investigations past checkpoint 1
-			if inv.Status == "complete" || inv.Status == "waiting" {
-				cmds = append(cmds, loadPhase1FindingsCmd(inv.ID))
-			}
-			return m, tea.Batch(cmds...)
+			return m, m.selectInvestigation()
 		}
-		return m, nil
+		return m, notifyCmd
 
 	case spinner.TickMsg:
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 
 	case summaryLoadedMsg:
+		if m.isStale(loadKey{kind: loadSummary, investigationID: msg.investigationID}, msg.gen) {
+			return m, nil
+		}
+		delete(m.failingLoads, "load summary")
 		if msg.summary != nil {
 			m.summaries[msg.investigationID] = msg.summary
 		}
 		return m, nil
 
 	case customerResponseLoadedMsg:
+		if m.isStale(loadKey{kind: loadCustomerResponse, investigationID: msg.investigationID}, msg.gen) {
+			return m, nil
+		}
 		if msg.response != nil {
 			m.customerResponses[msg.investigationID] = msg.response
 		}
+		m.diskStates[docKey{msg.investigationID, "customer-response.md"}] = msg.disk
+		m.lintSavedResponse(msg.investigationID)
+		if msg.response != nil {
+			return m, m.rememberGenerated(msg.investigationID, msg.response.Content)
+		}
 		return m, nil
 
+	// Action results are applied to the investigation the action was issued
+	// for, which may no longer be the selected one.
 	case responseCopiedMsg:
-		inv := m.getSelectedInvestigation()
-		if inv != nil && m.customerResponses[inv.ID] != nil {
-			m.customerResponses[inv.ID].CopiedToClip = true
+		if resp := m.customerResponses[msg.investigationID]; resp != nil {
+			resp.CopiedToClip = true
 		}
-		return m, nil
+		return m, m.notify(severityInfo, "copy", copiedNotice("response", msg.method), nil)
 
 	case responseSavedMsg:
-		inv := m.getSelectedInvestigation()
-		if inv != nil && m.customerResponses[inv.ID] != nil {
-			// Update the response with saved content
-			m.customerResponses[inv.ID].Content = m.responseTextarea.Value()
-			m.customerResponses[inv.ID].LastEdited = time.Now()
+		if resp := m.customerResponses[msg.investigationID]; resp != nil {
+			resp.Content = msg.content
+			resp.LastEdited = time.Now()
 		}
+		m.settleDiskState(docKey{msg.investigationID, "customer-response.md"}, msg.disk)
+		m.lintSavedResponse(msg.investigationID)
 		m.editingResponse = false
-		return m, nil
+		m.closeModal(modalConfirm)
+		return m, m.captureEdit(msg.investigationID, "saved", msg.content)
 
 	case responsePostedMsg:
+		m.finishOutward()
+		source := "post to Pylon (" + publisher.Name() + ")"
+		if msg.err != nil {
+			// Nothing was published, so the response stays unposted
+			return m, m.notify(severityError, source, msg.err.Error(), nil)
+		}
+		receipt := msg.receipt
+		if resp := m.customerResponses[msg.investigationID]; resp != nil {
+			resp.Posted = &receipt
+			resp.PostedToPylon = receipt.Publisher != "dry-run"
+		}
+		var cmds []tea.Cmd
+		if receipt.Publisher == "dry-run" {
+			cmds = append(cmds, m.notify(severityInfo, source, "Dry run: written to "+receipt.Location, nil))
+		} else {
+			cmds = append(cmds, m.notify(severityInfo, source, fmt.Sprintf("Posted to #%d as %s", msg.investigationID, receipt.MessageID), nil))
+		}
+		if msg.recordErr != nil {
+			cmds = append(cmds, m.notify(severityWarning, source, "Posted, but the receipt wasn't saved: "+msg.recordErr.Error(), nil))
+		}
+		cmds = append(cmds, m.captureEdit(msg.investigationID, "posted", msg.content))
+		return m, tea.Batch(cmds...)
+
+	case linearDraftLoadedMsg:
+		if m.isStale(loadKey{kind: loadLinearDraft, investigationID: msg.investigationID}, msg.gen) {
+			return m, nil
+		}
+		m.linearDrafts[msg.investigationID] = msg.draft
+		m.diskStates[docKey{msg.investigationID, "linear-draft.md"}] = msg.disk
+		// Never overwrite the form while it's being edited
 		inv := m.getSelectedInvestigation()
-		if inv != nil && m.customerResponses[inv.ID] != nil {
-			m.customerResponses[inv.ID].PostedToPylon = true
+		if inv != nil && inv.ID == msg.investigationID && !m.editingDraft && !m.draftDirty {
+			m.fillDraftForm(msg.investigationID, msg.draft)
+			m.beginEdit(docKey{msg.investigationID, "linear-draft.md"})
+		}
+		return m, nil
+
+	case linearDraftSavedMsg:
+		if msg.err != nil {
+			return m, m.notify(severityError, "save Linear draft", msg.err.Error(), nil)
 		}
-		m.showConfirmDialog = false
+		m.linearDrafts[msg.investigationID] = msg.draft
+		m.settleDiskState(docKey{msg.investigationID, "linear-draft.md"}, msg.disk)
+		if m.draftFormFor == msg.investigationID {
+			m.draftDirty = false
+		}
+		m.closeModal(modalConfirm)
+		return m, m.notify(severityInfo, "save Linear draft", fmt.Sprintf("Saved linear-draft.md for #%d", msg.investigationID), nil)
+
+	case linearIssueFiledMsg:
+		m.finishOutward()
+		if msg.err != nil {
+			return m, m.notify(severityError, "file Linear issue", msg.err.Error(), nil)
+		}
+		for i := range m.investigations {
+			if m.investigations[i].ID == msg.investigationID {
+				m.investigations[i].LinearIssueID = msg.identifier
+			}
+		}
+		notice := fmt.Sprintf("Filed #%d as %s", msg.investigationID, msg.identifier)
+		if msg.url != "" {
+			notice += " " + msg.url
+		}
+		return m, tea.Batch(
+			m.notify(severityInfo, "file Linear issue", notice, nil),
+			recordLinearIssueCmd(msg.investigationID, msg.identifier),
+		)
+
+	case editCapturedMsg:
+		if msg.err != nil {
+			return m, m.notify(severityWarning, "learn from edits", msg.err.Error(), nil)
+		}
+		return m, nil
+
+	case editInsightsLoadedMsg:
+		m.editInsights = msg.report
+		m.editInsightsLoading = false
 		return m, nil
 
+	case metricsLoadedMsg:
+		m.metrics = msg.report
+		m.metricsLoading = false
+		return m, nil
+
+	case kbArticleLoadedMsg:
+		if m.isStale(loadKey{kind: loadKBArticle, investigationID: msg.investigationID}, msg.gen) {
+			return m, nil
+		}
+		delete(m.failingLoads, "load KB article")
+		m.kbArticles[msg.investigationID] = msg.article
+		m.diskStates[docKey{msg.investigationID, kbArticleFile}] = msg.disk
+		if msg.article != nil && m.kbGenerating[msg.investigationID] {
+			delete(m.kbGenerating, msg.investigationID)
+			return m, m.notify(severityInfo, "generate KB article", fmt.Sprintf("KB draft for #%d is ready", msg.investigationID), nil)
+		}
+		return m, nil
+
+	case kbArticleSavedMsg:
+		m.kbArticles[msg.investigationID] = msg.article
+		m.settleDiskState(docKey{msg.investigationID, kbArticleFile}, msg.disk)
+		m.editingKB = false
+		m.kbTextarea.Blur()
+		m.closeModal(modalConfirm)
+		return m, nil
+
+	case kbExportedMsg:
+		if msg.err != nil {
+			return m, m.notify(severityError, "export KB article", msg.err.Error(), nil)
+		}
+		return m, m.notify(severityInfo, "export KB article", "Exported to "+msg.path, nil)
+
+	case kbGeneratedMsg:
+		if msg.err != nil {
+			delete(m.kbGenerating, msg.investigationID)
+			return m, m.notify(severityError, "generate KB article", msg.err.Error(), nil)
+		}
+		// Loading the article reports it as ready
+		return m, m.kbArticleCmd(msg.investigationID)
+
 	case agentStatusesLoadedMsg:
+		if m.isStale(loadKey{kind: loadAgentStatuses, investigationID: msg.investigationID}, msg.gen) {
+			return m, nil
+		}
+		delete(m.failingLoads, "load agents")
 		if m.agents[msg.investigationID] == nil {
 			m.agents[msg.investigationID] = make(map[string]*AgentState)
 		}
@@ -258,6 +505,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case agentLogsLoadedMsg:
+		if m.isStale(agentKey(loadAgentLogs, msg.investigationID, msg.agentName), msg.gen) {
+			return m, nil
+		}
 		if m.agents[msg.investigationID] != nil {
 			if state := m.agents[msg.investigationID][msg.agentName]; state != nil {
 				state.Logs = msg.logs
@@ -268,6 +518,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case agentFindingsLoadedMsg:
+		if m.isStale(agentKey(loadAgentFindings, msg.investigationID, msg.agentName), msg.gen) {
+			return m, nil
+		}
+		delete(m.failingLoads, "load findings")
 		if m.agents[msg.investigationID] == nil {
 			m.agents[msg.investigationID] = make(map[string]*AgentState)
 		}
@@ -290,8 +544,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case checkpointApprovedMsg:
 		// Reload investigation data after approval
 		return m, tea.Batch(
-			loadInvestigationsCmd(),
-			loadAgentStatusesCmd(msg.investigationID),
+			m.investigationsCmd(),
+			m.agentStatusesCmd(msg.investigationID),
 		)
 
 	case investigationCreatedMsg:
@@ -301,9 +555,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		// Success: close form, reload investigations
-		m.showCreateForm = false
+		m.closeModal(modalCreate)
 		m.createError = ""
-		return m, loadInvestigationsCmd()
+		return m, m.investigationsCmd()
 
 	case hardResetCompletedMsg:
 		m.resettingInProgress = false
@@ -311,9 +565,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.resetError = msg.err.Error()
 			return m, nil
 		}
-		m.showResetForm = false
+		m.closeModal(modalReset)
 		m.resetError = ""
-		return m, loadInvestigationsCmd()
+		return m, m.investigationsCmd()
 
 	case newRunApprovedMsg:
 		m.approvingReply = false
@@ -321,16 +575,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.replyError = msg.err.Error()
 			return m, nil
 		}
-		m.showReplyPrompt = false
+		m.closeModal(modalReply)
 		m.replyError = ""
-		return m, loadInvestigationsCmd()
+		return m, m.investigationsCmd()
 
 	case replyDismissedMsg:
-		m.showReplyPrompt = false
+		m.closeModal(modalReply)
 		m.replyError = ""
-		return m, loadInvestigationsCmd()
+		if msg.err != nil {
+			toast := m.notify(severityWarning, "dismiss reply", msg.err.Error(), dismissReplyCmd(msg.investigationID))
+			return m, tea.Batch(toast, m.investigationsCmd())
+		}
+		return m, m.investigationsCmd()
 
 	case ticketDataLoadedMsg:
+		if m.isStale(loadKey{kind: loadTicketData, investigationID: msg.investigationID}, msg.gen) {
+			return m, nil
+		}
+		delete(m.failingLoads, "load ticket data")
 		if msg.data != nil {
 			m.ticketData[msg.investigationID] = msg.data
 			// Initialize cp1 editable fields if this is the selected investigation
@@ -348,6 +610,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case phase1FindingsLoadedMsg:
+		if m.isStale(loadKey{kind: loadPhase1Findings, investigationID: msg.investigationID}, msg.gen) {
+			return m, nil
+		}
 		if msg.content != "" {
 			m.phase1Findings[msg.investigationID] = msg.content
 		}
@@ -355,15 +620,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case investigationUpdatedMsg:
 		if msg.err != nil {
-			m.err = msg.err
-			return m, nil
+			return m, m.notify(severityError, "update investigation", msg.err.Error(), nil)
 		}
-		// After update, approve the checkpoint
-		inv := m.getSelectedInvestigation()
-		if inv != nil && inv.ID == msg.investigationID {
-			return m, approveCheckpointCmd(inv.ID, inv.CurrentCheckpoint)
+		// After update, approve the checkpoint of the investigation that was
+		// updated, wherever the selection has moved since
+		inv := m.findInvestigation(msg.investigationID)
+		if inv == nil {
+			return m, m.notify(severityWarning, "approve checkpoint",
+				fmt.Sprintf("#%d is no longer listed; its checkpoint wasn't approved", msg.investigationID), nil)
 		}
-		return m, nil
+		return m, approveCheckpointCmd(inv.ID, inv.CurrentCheckpoint)
 
 	case tickMsg:
 		// Periodic refresh for running investigations
@@ -379,28 +645,49 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		if hasActive {
-			cmds = append(cmds, loadInvestigationsCmd())
+			cmds = append(cmds, m.investigationsCmd())
 		}
 
 		// Only poll agent data for the selected investigation, and only when running
 		selInv := m.getSelectedInvestigation()
 		if selInv != nil && selInv.Status == "running" {
-			cmds = append(cmds, loadAgentStatusesCmd(selInv.ID))
+			cmds = append(cmds, m.agentStatusesCmd(selInv.ID))
 			agentName := m.getActiveAgentName()
 			if agentName != "" {
 				cmds = append(cmds,
-					streamAgentLogsCmd(selInv.ID, agentName),
-					loadAgentFindingsCmd(selInv.ID, agentName),
+					m.agentLogsCmd(selInv.ID, agentName),
+					m.agentFindingsCmd(selInv.ID, agentName),
 				)
 			}
-			cmds = append(cmds, loadPhase1FindingsCmd(selInv.ID))
+			cmds = append(cmds, m.phase1FindingsCmd(selInv.ID))
+			if pids := m.runningAgentPIDs(selInv.ID); len(pids) > 0 {
+				cmds = append(cmds, sampleAgentProcsCmd(pids))
+			}
+		}
+		if now := time.Time(msg); m.watchdogDue(now) {
+			m.watchdogCheckedAt = now
+			cmds = append(cmds, watchdogCmd(m.investigations, m.settings.agentIdleTimeout(), m.settings.investigationMaxDuration()))
+		}
+		if now := time.Time(msg); now.Sub(m.autosavedAt) >= autosaveInterval {
+			m.autosavedAt = now
+			if write, remove := m.autosavePlan(now); len(write) > 0 || len(remove) > 0 {
+				cmds = append(cmds, autosaveCmd(draftsDir, write, remove))
+			}
+		}
+		if m.recoveryDue() {
+			m.recoveryCursor = 0
+			m.pushModal(modalRecovery)
+		}
+		// Watch for a requested KB draft to land
+		if selInv != nil && m.kbGenerating[selInv.ID] {
+			cmds = append(cmds, m.kbArticleCmd(selInv.ID))
 		}
 
 		// Auto-show reply prompt when selected investigation has a new reply
-		if !m.showReplyPrompt && !m.showResetForm && !m.showConfirmDialog && !m.showCreateForm && !m.editingResponse {
+		if m.topModal() == modalNone && !m.editingResponse {
 			inv := m.getSelectedInvestigation()
 			if inv != nil && inv.HasNewReply == 1 {
-				m.showReplyPrompt = true
+				m.pushModal(modalReply)
 				m.replyContextArea.SetValue("")
 				m.replyError = ""
 				m.approvingReply = false
@@ -409,149 +696,237 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return m, tea.Batch(cmds...)
 
-	case errMsg:
-		m.err = msg.err
-		return m, nil
+	case outwardRefusedMsg:
+		m.finishOutward()
+		return m, m.notify(severityError, "safety", msg.err.Error(), nil)
 
-	case tea.KeyMsg:
-		// Handle checkpoint 1 review card keyboard
-		if m.isShowingCP1Review() {
-			return m.handleCP1Key(msg)
+	case settingsLoadedMsg:
+		if msg.err != nil {
+			// Keep the last good settings; with none, approval stays required
+			return m, m.notify(severityWarning, "load settings", msg.err.Error(), loadSettingsCmd())
+		}
+		m.settings = msg.settings
+		m.relintResponses()
+		// Responses that loaded before the settings did
+		var cmds []tea.Cmd
+		for id, response := range m.customerResponses {
+			cmds = append(cmds, m.rememberGenerated(id, response.Content))
 		}
+		return m, tea.Batch(cmds...)
 
-		// Handle confirmation dialog
-		if m.showConfirmDialog {
-			switch {
-			case key.Matches(msg, keys.Yes), key.Matches(msg, keys.Enter):
-				inv := m.getSelectedInvestigation()
-				if inv != nil && m.confirmAction == "post" {
-					response := m.getCustomerResponse(inv.ID)
-					if response != nil {
-						return m, postToPylonCmd(inv.ID, response.Content)
-					}
-				} else if inv != nil && m.confirmAction == "save" {
-					return m, saveCustomerResponseCmd(inv.ID, m.responseTextarea.Value())
-				}
-				m.showConfirmDialog = false
-				return m, nil
+	case agentStoppedMsg:
+		if msg.err != nil {
+			return m, m.notify(severityError, "stop agent", msg.err.Error(), nil)
+		}
+		notice := fmt.Sprintf("Stopped %s agent on #%d", msg.agentName, msg.investigationID)
+		return m, tea.Batch(
+			m.notify(severityInfo, "stop agent", notice, nil),
+			m.agentStatusesCmd(msg.investigationID),
+		)
 
-			case key.Matches(msg, keys.No), key.Matches(msg, keys.Escape):
-				m.showConfirmDialog = false
-				return m, nil
+	case agentRetriedMsg:
+		if m.agentRetryPending > 0 {
+			m.agentRetryPending--
+		}
+		if msg.err != nil {
+			// Keep the dialog open so the context isn't lost
+			if m.isModalOpen(modalAgentRetry) {
+				m.agentRetryError = fmt.Sprintf("%s: %v", msg.agentName, msg.err)
+			}
+			return m, m.notify(severityError, "retry agent", fmt.Sprintf("%s: %v", msg.agentName, msg.err),
+				retryAgentCmd(msg.investigationID, msg.agentName, strings.TrimSpace(m.agentRetryArea.Value())))
+		}
+		if m.agentRetryPending == 0 && m.agentRetryError == "" {
+			m.agentRetryArea.Blur()
+			m.closeModal(modalAgentRetry)
+		}
+		return m, tea.Batch(
+			m.notify(severityInfo, "retry agent", fmt.Sprintf("Retrying %s agent on #%d", msg.agentName, msg.investigationID), nil),
+			m.agentStatusesCmd(msg.investigationID),
+			m.investigationsCmd(),
+		)
+
+	case procSampledMsg:
+		var pids []int
+		for pid, sample := range msg.samples {
+			if m.procHistory[pid] == nil {
+				m.procHistory[pid] = &procHistory{}
+			}
+			m.procHistory[pid].add(sample)
+			pids = append(pids, pid)
+		}
+		m.pruneProcHistory(pids)
+		return m, nil
+
+	case watchdogCheckedMsg:
+		// Warn once when an investigation first goes quiet
+		var cmds []tea.Cmd
+		for id, h := range msg.reports {
+			if h.flagged() && !m.watchdog[id].flagged() {
+				cmds = append(cmds, m.notify(severityWarning, "watchdog",
+					fmt.Sprintf("#%d may be stalled: %s (ctrl+r to retry)", id, h.problems()[0]), nil))
 			}
+		}
+		m.watchdog = msg.reports
+		return m, tea.Batch(cmds...)
+
+	case investigationRetriedMsg:
+		delete(m.watchdog, msg.investigationID)
+		return m, tea.Batch(
+			m.notify(severityInfo, "retry investigation", fmt.Sprintf("Retrying #%d", msg.investigationID), nil),
+			m.investigationsCmd(),
+		)
+
+	case settingsDocLoadedMsg:
+		if !m.isModalOpen(modalSettings) {
 			return m, nil
 		}
+		m.settingsEdit.loading = false
+		if msg.err != nil {
+			m.settingsEdit.loadErr = msg.err
+			return m, nil
+		}
+		m.settingsEdit.load(msg.doc)
+		return m, nil
 
-		// Handle create form input
-		if m.showCreateForm && !m.creatingInProgress {
-			switch {
-			case key.Matches(msg, keys.Escape):
-				m.showCreateForm = false
-				m.createError = ""
-				return m, nil
+	case settingsSavedMsg:
+		m.settingsEdit.saving = false
+		if msg.err != nil {
+			m.settingsEdit.saveErr = msg.err
+			return m, m.notify(severityError, "save settings", msg.err.Error(), nil)
+		}
+		m.settings = msg.settings
+		m.relintResponses()
+		m.closeModal(modalSettings)
+		return m, m.notify(severityInfo, "settings", "Settings saved", nil)
 
-			case key.Matches(msg, keys.Save):
-				// Ctrl+S submits
-				return m.submitCreateForm()
-
-			case key.Matches(msg, keys.Enter):
-				if m.createFocusField == 1 {
-					// Cycle skill option when on skill field
-					m.createSkill = (m.createSkill + 1) % len(skillOptions)
-					return m, nil
-				}
-				// Submit from ticket or context field
-				return m.submitCreateForm()
+	case snippetsLoadedMsg:
+		m.snippets, m.snippetsErr = msg.snippets, msg.err
+		if msg.err != nil {
+			return m, m.notify(severityWarning, "load snippets", msg.err.Error(), nil)
+		}
+		return m, nil
 
-			default:
-				keyStr := msg.String()
-				if keyStr == "tab" || keyStr == "shift+tab" {
-					// Cycle focus between fields
-					if keyStr == "tab" {
-						m.createFocusField = (m.createFocusField + 1) % 3
-					} else {
-						m.createFocusField = (m.createFocusField + 2) % 3
-					}
-					// Update focus state
-					if m.createFocusField == 0 {
-						m.createTicketInput.Focus()
-						m.createContextArea.Blur()
-					} else if m.createFocusField == 1 {
-						m.createTicketInput.Blur()
-						m.createContextArea.Blur()
-					} else {
-						m.createTicketInput.Blur()
-						m.createContextArea.Focus()
-					}
-					return m, nil
-				}
+	case snippetSavedMsg:
+		if msg.err != nil {
+			m.newSnippetErr = msg.err
+			return m, nil
+		}
+		m.newSnippetName.Blur()
+		m.closeModal(modalNewSnippet)
+		return m, tea.Batch(
+			m.notify(severityInfo, "snippets", fmt.Sprintf("Saved snippet %q to %s", msg.name, msg.path), nil),
+			loadSnippetsCmd(snippetsDir),
+		)
 
-				// Route input to focused field
-				if m.createFocusField == 0 {
-					m.createTicketInput, cmd = m.createTicketInput.Update(msg)
-					return m, cmd
-				} else if m.createFocusField == 1 {
-					// Skill field: space or arrows cycle options
-					if keyStr == " " || keyStr == "left" || keyStr == "right" {
-						m.createSkill = (m.createSkill + 1) % len(skillOptions)
-					}
-					return m, nil
-				} else {
-					m.createContextArea, cmd = m.createContextArea.Update(msg)
-					return m, cmd
-				}
-			}
+	case draftsRecoveredMsg:
+		if msg.err != nil {
+			return m, m.notify(severityWarning, "recover drafts", msg.err.Error(), nil)
 		}
+		m.recovered = msg.drafts
+		return m, nil
 
-		// Handle reset form input
-		if m.showResetForm && !m.resettingInProgress {
-			switch {
-			case key.Matches(msg, keys.Escape):
-				m.showResetForm = false
-				m.resetError = ""
-				return m, nil
+	case draftsAutosavedMsg:
+		// A failing disk would fail every few seconds; say so once per error
+		if msg.err == nil {
+			m.autosaveErr = ""
+			return m, nil
+		}
+		if msg.err.Error() == m.autosaveErr {
+			return m, nil
+		}
+		m.autosaveErr = msg.err.Error()
+		return m, m.notify(severityWarning, "autosave", msg.err.Error(), nil)
 
-			case key.Matches(msg, keys.Save), key.Matches(msg, keys.Enter):
-				inv := m.getSelectedInvestigation()
-				if inv != nil {
-					m.resettingInProgress = true
-					m.resetError = ""
-					return m, hardResetCmd(inv.ID, strings.TrimSpace(m.resetContextArea.Value()))
-				}
-				return m, nil
+	case saveConflictMsg:
+		m.openConflict(msg.conflict)
+		return m, nil
 
-			default:
-				var cmd tea.Cmd
-				m.resetContextArea, cmd = m.resetContextArea.Update(msg)
-				return m, cmd
-			}
+	case versionsLoadedMsg:
+		if m.isStale(loadKey{kind: loadVersions, investigationID: msg.investigationID}, msg.gen) {
+			return m, nil
+		}
+		if msg.investigationID == m.history.investigationID {
+			m.history.loading = false
+			m.history.err = msg.err
+		}
+		if msg.err != nil {
+			return m, nil
 		}
+		m.versions[msg.investigationID] = msg.versions
+		if m.history.cursor >= len(msg.versions) {
+			m.history.cursor = 0
+		}
+		return m, nil
 
-		// Handle reply prompt input
-		if m.showReplyPrompt && !m.approvingReply {
-			switch {
-			case key.Matches(msg, keys.Escape), key.Matches(msg, keys.No):
-				inv := m.getSelectedInvestigation()
-				if inv != nil {
-					return m, dismissReplyCmd(inv.ID)
-				}
-				m.showReplyPrompt = false
-				return m, nil
+	case versionRestoredMsg:
+		m.history.restoring = false
+		if msg.err != nil {
+			return m, m.notify(severityError, "restore version", msg.err.Error(), nil)
+		}
+		// The rollback rewrote the files and the investigation row
+		m.history.cursor = 0
+		return m, tea.Batch(
+			m.notify(severityInfo, "restore version", fmt.Sprintf("#%d: %s", msg.investigationID, msg.message), nil),
+			m.investigationsCmd(),
+			m.summaryCmd(msg.investigationID),
+			m.customerResponseCmd(msg.investigationID),
+			m.versionsCmd(msg.investigationID),
+		)
 
-			case key.Matches(msg, keys.Yes), key.Matches(msg, keys.Enter):
-				inv := m.getSelectedInvestigation()
-				if inv != nil {
-					m.approvingReply = true
-					m.replyError = ""
-					return m, approveNewRunCmd(inv.ID, strings.TrimSpace(m.replyContextArea.Value()))
-				}
-				return m, nil
+	case externalEditDoneMsg:
+		return m, m.finishExternalEdit(msg)
 
-			default:
-				var cmd tea.Cmd
-				m.replyContextArea, cmd = m.replyContextArea.Update(msg)
-				return m, cmd
+	case textCopiedMsg:
+		return m, m.notify(severityInfo, "copy", copiedNotice(msg.what, msg.method), nil)
+
+	case errMsg:
+		// Only unrecoverable errors replace the UI; the rest are toasts
+		if msg.fatal {
+			m.err = msg.err
+			return m, nil
+		}
+		// A poll that keeps failing was reported the first time
+		if msg.poll {
+			if m.failingLoads[msg.source] {
+				return m, nil
 			}
+			m.failingLoads[msg.source] = true
+		}
+		return m, m.notify(severityError, msg.source, msg.err.Error(), msg.retry)
+
+	case toastExpiredMsg:
+		m.dismissToast(msg.id)
+		return m, nil
+
+	case reloadMsg:
+		return m, m.reload(msg)
+
+	case tea.KeyMsg:
+		// Dialogs on the modal stack take every key before the layout below
+		if m.topModal() != modalNone {
+			return m.handleModalKey(msg)
+		}
+
+		if key.Matches(msg, keys.Palette) && m.paletteAvailable() {
+			return m, m.openPalette()
+		}
+
+		// Handle checkpoint 1 review card keyboard
+		if m.isShowingCP1Review() {
+			return m.handleCP1Key(msg)
+		}
+
+		if m.selecting {
+			return m.handleSelectionKey(msg)
+		}
+
+		if m.editingDraft {
+			return m.handleLinearDraftKey(msg)
+		}
+
+		if m.editingKB {
+			return m.handleKBEditKey(msg)
 		}
 
 		// Handle textarea input when editing
@@ -559,11 +934,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch {
 			case key.Matches(msg, keys.Save):
 				// Show save confirmation
-				m.showConfirmDialog = true
+				m.pushModal(modalConfirm)
 				m.confirmAction = "save"
 				m.confirmMessage = "Save changes to customer response?"
 				return m, nil
 
+			case key.Matches(msg, keys.Snippets):
+				return m, m.openSnippetPicker()
+
 			case key.Matches(msg, keys.Escape):
 				// Cancel editing
 				m.editingResponse = false
@@ -573,10 +951,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Pass all other keys to textarea
 				var cmd tea.Cmd
 				m.responseTextarea, cmd = m.responseTextarea.Update(msg)
+				m.lintEditedResponse()
 				return m, cmd
 			}
 		}
 
+		if m.history.open && m.activeTab == TabSummary {
+			return m.handleVersionHistoryKey(msg)
+		}
+
 		// Normal keyboard handling
 		switch {
 		case key.Matches(msg, keys.Quit):
@@ -584,52 +967,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, keys.Up):
 			if m.selectedIndex > 0 {
-				m.selectedIndex--
-				m.cp1Loaded = 0 // Reset so cp1 fields reload for new selection
-				// Load data for newly selected investigation
-				inv := m.getSelectedInvestigation()
-				if inv != nil {
-					cmds := []tea.Cmd{
-						loadAgentStatusesCmd(inv.ID),
-						streamAgentLogsCmd(inv.ID, m.getActiveAgentName()),
-						loadAgentFindingsCmd(inv.ID, m.getActiveAgentName()),
-					}
-					if inv.Status == "waiting" && inv.CurrentCheckpoint == "checkpoint_1_post_classification" {
-						cmds = append(cmds, loadTicketDataCmd(inv.ID))
-					}
-					if inv.Status == "complete" {
-						cmds = append(cmds, loadPhase1FindingsCmd(inv.ID))
-					}
-					return m, tea.Batch(cmds...)
-				}
+				return m, m.moveSelection(m.selectedIndex - 1)
 			}
 			return m, nil
 
 		case key.Matches(msg, keys.Down):
 			if m.selectedIndex < len(m.investigations)-1 {
-				m.selectedIndex++
-				m.cp1Loaded = 0 // Reset so cp1 fields reload for new selection
-				// Load data for newly selected investigation
-				inv := m.getSelectedInvestigation()
-				if inv != nil {
-					cmds := []tea.Cmd{
-						loadAgentStatusesCmd(inv.ID),
-						streamAgentLogsCmd(inv.ID, m.getActiveAgentName()),
-						loadAgentFindingsCmd(inv.ID, m.getActiveAgentName()),
-					}
-					if inv.Status == "waiting" && inv.CurrentCheckpoint == "checkpoint_1_post_classification" {
-						cmds = append(cmds, loadTicketDataCmd(inv.ID))
-					}
-					if inv.Status == "complete" {
-						cmds = append(cmds, loadPhase1FindingsCmd(inv.ID))
-					}
-					return m, tea.Batch(cmds...)
-				}
+				return m, m.moveSelection(m.selectedIndex + 1)
 			}
 			return m, nil
 
 		case key.Matches(msg, keys.Refresh):
-			return m, loadInvestigationsCmd()
+			return m, m.investigationsCmd()
 
 		case key.Matches(msg, keys.Approve):
 			if m.hasCheckpoint() {
@@ -644,8 +993,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			inv := m.getSelectedInvestigation()
 			if inv != nil {
 				return m, tea.Batch(
-					streamAgentLogsCmd(inv.ID, "Slack"),
-					loadAgentFindingsCmd(inv.ID, "Slack"),
+					m.agentLogsCmd(inv.ID, "Slack"),
+					m.agentFindingsCmd(inv.ID, "Slack"),
 				)
 			}
 			return m, nil
@@ -655,8 +1004,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			inv := m.getSelectedInvestigation()
 			if inv != nil {
 				return m, tea.Batch(
-					streamAgentLogsCmd(inv.ID, "Linear"),
-					loadAgentFindingsCmd(inv.ID, "Linear"),
+					m.agentLogsCmd(inv.ID, "Linear"),
+					m.agentFindingsCmd(inv.ID, "Linear"),
 				)
 			}
 			return m, nil
@@ -666,8 +1015,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			inv := m.getSelectedInvestigation()
 			if inv != nil {
 				return m, tea.Batch(
-					streamAgentLogsCmd(inv.ID, "Pylon"),
-					loadAgentFindingsCmd(inv.ID, "Pylon"),
+					m.agentLogsCmd(inv.ID, "Pylon"),
+					m.agentFindingsCmd(inv.ID, "Pylon"),
 				)
 			}
 			return m, nil
@@ -677,8 +1026,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			inv := m.getSelectedInvestigation()
 			if inv != nil {
 				return m, tea.Batch(
-					streamAgentLogsCmd(inv.ID, "Codebase"),
-					loadAgentFindingsCmd(inv.ID, "Codebase"),
+					m.agentLogsCmd(inv.ID, "Codebase"),
+					m.agentFindingsCmd(inv.ID, "Codebase"),
 				)
 			}
 			return m, nil
@@ -689,20 +1038,29 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			inv := m.getSelectedInvestigation()
 			if inv != nil {
 				return m, tea.Batch(
-					loadSummaryCmd(inv.ID),
-					loadCustomerResponseCmd(inv.ID),
+					m.summaryCmd(inv.ID),
+					m.customerResponseCmd(inv.ID),
 				)
 			}
 			return m, nil
 
-		// case key.Matches(msg, keys.Tab6):
-		// 	m.activeTab = TabKB
-		// 	return m, nil
-		// NOTE: Tab 6 (KB Article) disabled - V2 feature
+		case key.Matches(msg, keys.Tab6):
+			m.activeTab = TabLinearDraft
+			if inv := m.getSelectedInvestigation(); inv != nil {
+				return m, m.linearDraftCmd(inv.ID)
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Tab7):
+			m.activeTab = TabKB
+			if inv := m.getSelectedInvestigation(); inv != nil {
+				return m, m.kbArticleCmd(inv.ID)
+			}
+			return m, nil
 
 		case key.Matches(msg, keys.TabNext):
-			// Cycle through tabs (only 5 tabs: 0-4)
-			m.activeTab = (m.activeTab + 1) % 5
+			// Cycle through tabs (7 tabs: 0-6)
+			m.activeTab = (m.activeTab + 1) % 7
 
 			// Load data based on which tab we switched to
 			inv := m.getSelectedInvestigation()
@@ -711,20 +1069,31 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Agent tabs: load logs and findings
 					agentName := m.getActiveAgentName()
 					return m, tea.Batch(
-						streamAgentLogsCmd(inv.ID, agentName),
-						loadAgentFindingsCmd(inv.ID, agentName),
+						m.agentLogsCmd(inv.ID, agentName),
+						m.agentFindingsCmd(inv.ID, agentName),
 					)
 				} else if m.activeTab == TabSummary {
 					// Summary tab: load summary and response
 					return m, tea.Batch(
-						loadSummaryCmd(inv.ID),
-						loadCustomerResponseCmd(inv.ID),
+						m.summaryCmd(inv.ID),
+						m.customerResponseCmd(inv.ID),
 					)
+				} else if m.activeTab == TabLinearDraft {
+					return m, m.linearDraftCmd(inv.ID)
+				} else if m.activeTab == TabKB {
+					return m, m.kbArticleCmd(inv.ID)
 				}
 			}
 			return m, nil
 
 		case key.Matches(msg, keys.PageUp):
+			if m.activeTab == TabKB {
+				m.kbScroll -= 10
+				if m.kbScroll < 0 {
+					m.kbScroll = 0
+				}
+				return m, nil
+			}
 			// Scroll terminal viewport up
 			if m.ready {
 				m.terminalViewport.LineUp(5)
@@ -732,6 +1101,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case key.Matches(msg, keys.PageDown):
+			if m.activeTab == TabKB {
+				// Clamped when rendering
+				m.kbScroll += 10
+				return m, nil
+			}
 			// Scroll terminal viewport down
 			if m.ready {
 				m.terminalViewport.LineDown(5)
@@ -739,6 +1113,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case key.Matches(msg, keys.Edit):
+			if m.activeTab == TabKB {
+				if inv := m.getSelectedInvestigation(); inv != nil && m.kbArticles[inv.ID] != nil {
+					return m, m.startKBEdit(inv.ID, m.kbArticles[inv.ID])
+				}
+				return m, nil
+			}
+			if m.activeTab == TabLinearDraft {
+				if inv := m.getSelectedInvestigation(); inv != nil && m.draftFormFor == inv.ID {
+					m.startDraftEdit()
+				}
+				return m, nil
+			}
 			// Edit customer response (only on Summary tab)
 			if m.activeTab == TabSummary && !m.editingResponse {
 				inv := m.getSelectedInvestigation()
@@ -746,45 +1132,140 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					response := m.getCustomerResponse(inv.ID)
 					if response != nil {
 						m.editingResponse = true
+						m.beginEdit(docKey{inv.ID, "customer-response.md"})
 						m.responseTextarea.SetValue(response.Content)
 						m.responseTextarea.Focus()
+						m.lintEditedResponse()
 					}
 				}
 			}
 			return m, nil
 
+		case key.Matches(msg, keys.History):
+			if inv := m.getSelectedInvestigation(); inv != nil && m.activeTab == TabSummary {
+				return m, m.openVersionHistory(inv)
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.EditExternal):
+			return m, m.openExternalEditor()
+
+		case key.Matches(msg, keys.Select):
+			return m, m.enterSelection()
+
 		case key.Matches(msg, keys.Copy):
+			if m.activeTab == TabKB {
+				if inv := m.getSelectedInvestigation(); inv != nil && m.kbArticles[inv.ID] != nil {
+					return m, copyTextCmd("KB article", m.kbArticles[inv.ID].Content)
+				}
+				return m, nil
+			}
+			if m.activeTab == TabLinearDraft {
+				if inv := m.getSelectedInvestigation(); inv != nil && m.draftFormFor == inv.ID && m.linearDrafts[inv.ID] != nil {
+					return m, copyTextCmd("Linear draft", m.formDraft().Markdown())
+				}
+				return m, nil
+			}
 			// Copy customer response to clipboard (only on Summary tab)
 			if m.activeTab == TabSummary {
 				inv := m.getSelectedInvestigation()
 				if inv != nil {
 					response := m.getCustomerResponse(inv.ID)
 					if response != nil {
-						return m, copyToClipboardCmd(response.Content)
+						return m, copyToClipboardCmd(inv.ID, response.Content)
 					}
 				}
 			}
 			return m, nil
 
 		case key.Matches(msg, keys.Post):
+			if m.activeTab == TabLinearDraft {
+				return m, m.fileLinearDraft()
+			}
 			// Show confirmation before posting to Pylon
 			if m.activeTab == TabSummary {
 				inv := m.getSelectedInvestigation()
 				if inv != nil && m.customerResponses[inv.ID] != nil {
-					if !m.customerResponses[inv.ID].PostedToPylon {
-						m.showConfirmDialog = true
-						m.confirmAction = "post"
-						m.confirmMessage = fmt.Sprintf("Post response to Pylon ticket #%d?", inv.ID)
+					if response := m.customerResponses[inv.ID]; !response.PostedToPylon {
+						// Check the text being posted, not a lint from before a reload
+						findings := lintResponse(response.Content, m.settings.responseStyle())
+						m.responseLints[inv.ID] = findings
+						if blocker := firstLintError(findings); blocker != nil {
+							errs, _ := lintCounts(findings)
+							return m, m.notify(severityError, "post to Pylon",
+								fmt.Sprintf("Fix %d style check error(s) before posting, starting with: %s", errs, blocker.Message), nil)
+						}
+						title := fmt.Sprintf("Post response to Pylon ticket #%d", inv.ID)
+						if publisher.Name() == "dry-run" {
+							title += " (dry run)"
+						}
+						return m, m.requestOutward(outwardRequest{
+							kind:            outwardPylon,
+							investigationID: inv.ID,
+							title:           title,
+							preview:         response.Content,
+							busyLabel:       "Posting via " + publisher.Name() + "...",
+							detail:          "publisher=" + publisher.Name(),
+							run:             postToPylonCmd(inv.ID, response.Content),
+						})
 					}
 				}
 			}
 			return m, nil
 
+		case key.Matches(msg, keys.Metrics):
+			return m, m.openMetrics()
+
+		case key.Matches(msg, keys.Insights):
+			return m, m.openEditInsights()
+
+		case key.Matches(msg, keys.Settings):
+			return m, m.openSettings()
+
+		case key.Matches(msg, keys.Retry):
+			if inv := m.getSelectedInvestigation(); inv != nil {
+				return m, m.confirmStalledRetry(inv)
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.StopAgent), key.Matches(msg, keys.RetryAgent):
+			// Agent tabs only
+			inv := m.getSelectedInvestigation()
+			if inv == nil || m.getActiveAgentName() == "" {
+				return m, nil
+			}
+			if key.Matches(msg, keys.StopAgent) {
+				return m, m.confirmStopAgent(inv)
+			}
+			return m, m.retryActiveAgent(inv)
+
+		case key.Matches(msg, keys.RetryFailed):
+			if inv := m.getSelectedInvestigation(); inv != nil {
+				return m, m.retryFailedAgents(inv)
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Export):
+			if m.activeTab == TabKB {
+				if inv := m.getSelectedInvestigation(); inv != nil && m.kbArticles[inv.ID] != nil {
+					return m, exportKBArticleCmd(inv.ID, m.kbArticles[inv.ID])
+				}
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Generate):
+			if m.activeTab == TabKB {
+				if inv := m.getSelectedInvestigation(); inv != nil {
+					return m, m.requestKBGeneration(inv)
+				}
+			}
+			return m, nil
+
 		case key.Matches(msg, keys.Reset):
 			// Shift+R: open reset form (only when investigation is not running)
 			inv := m.getSelectedInvestigation()
-			if inv != nil && inv.Status != "running" && !m.showReplyPrompt && !m.showConfirmDialog && !m.showCreateForm {
-				m.showResetForm = true
+			if inv != nil && inv.Status != "running" {
+				m.pushModal(modalReset)
 				m.resetContextArea.SetValue("")
 				m.resetError = ""
 				m.resettingInProgress = false
@@ -794,7 +1275,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case key.Matches(msg, keys.New):
-			m.showCreateForm = true
+			m.pushModal(modalCreate)
 			m.createFocusField = 0
 			m.createTicketInput.SetValue("")
 			m.createContextArea.SetValue("")
@@ -807,6 +1288,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, keys.Debug):
 			m.showDebugOverlay = !m.showDebugOverlay
 			return m, nil
+
+		case key.Matches(msg, keys.Errors):
+			m.pushModal(modalErrors)
+			m.errorPanelIndex = 0
+			return m, nil
 		}
 	}
 
@@ -835,6 +1321,189 @@ func (m model) submitCreateForm() (tea.Model, tea.Cmd) {
 	return m, createInvestigationCmd(ticketID, skill, context)
 }
 
+// handleConfirmKey handles the yes/no confirmation dialog
+func (m model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Yes), key.Matches(msg, keys.Enter):
+		inv := m.getSelectedInvestigation()
+		switch {
+		case inv != nil && m.confirmAction == "save":
+			return m, saveCustomerResponseCmd(inv.ID, m.responseTextarea.Value(), m.expectedDiskState(docKey{inv.ID, "customer-response.md"}))
+		case inv != nil && m.confirmAction == "save_kb":
+			return m, saveKBArticleCmd(inv.ID, m.kbTextarea.Value(), m.expectedDiskState(docKey{inv.ID, kbArticleFile}))
+		case inv != nil && m.confirmAction == "save_draft":
+			draft := m.formDraft()
+			m.draftProblems = draft.validate()
+			return m, saveLinearDraftCmd(inv.ID, draft, m.expectedDiskState(docKey{inv.ID, "linear-draft.md"}))
+		case m.confirmAction == "restore_version":
+			m.closeModal(modalConfirm)
+			v := m.selectedVersion()
+			if v == nil {
+				return m, nil
+			}
+			m.history.restoring = true
+			return m, restoreVersionCmd(m.history.investigationID, v.ID)
+		case m.confirmAction == "discard_draft":
+			m.closeModal(modalConfirm)
+			m.stopDraftEdit()
+			m.fillDraftForm(m.draftFormFor, m.linearDrafts[m.draftFormFor])
+			m.beginEdit(docKey{m.draftFormFor, "linear-draft.md"})
+			return m, nil
+		case inv != nil && m.confirmAction == "stop_agent":
+			m.closeModal(modalConfirm)
+			return m, stopAgentCmd(inv.ID, m.agentActionTarget)
+		case inv != nil && m.confirmAction == "retry_stalled":
+			m.closeModal(modalConfirm)
+			return m, retryInvestigationCmd(inv.ID)
+		case m.confirmAction == "discard_settings":
+			m.closeModal(modalConfirm)
+			m.closeModal(modalSettings)
+			return m, nil
+		case m.confirmAction == "discard_create":
+			// Confirmation stacked over the create form: close both
+			m.closeModal(modalConfirm)
+			m.closeModal(modalCreate)
+			m.createError = ""
+			return m, nil
+		}
+		m.closeModal(modalConfirm)
+		return m, nil
+
+	case key.Matches(msg, keys.No), key.Matches(msg, keys.Escape):
+		m.closeModal(modalConfirm)
+		return m, nil
+	}
+	return m, nil
+}
+
+// handleCreateFormKey handles input for the new investigation form
+func (m model) handleCreateFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if m.creatingInProgress {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, keys.Escape):
+		// Ask before throwing away anything typed into the form
+		if strings.TrimSpace(m.createTicketInput.Value()) != "" || strings.TrimSpace(m.createContextArea.Value()) != "" {
+			m.pushModal(modalConfirm)
+			m.confirmAction = "discard_create"
+			m.confirmMessage = "Discard this new investigation?"
+			return m, nil
+		}
+		m.closeModal(modalCreate)
+		m.createError = ""
+		return m, nil
+
+	case key.Matches(msg, keys.Save):
+		// Ctrl+S submits
+		return m.submitCreateForm()
+
+	case key.Matches(msg, keys.Enter):
+		if m.createFocusField == 1 {
+			// Cycle skill option when on skill field
+			m.createSkill = (m.createSkill + 1) % len(skillOptions)
+			return m, nil
+		}
+		// Submit from ticket or context field
+		return m.submitCreateForm()
+	}
+
+	keyStr := msg.String()
+	if keyStr == "tab" || keyStr == "shift+tab" {
+		// Cycle focus between fields
+		if keyStr == "tab" {
+			m.createFocusField = (m.createFocusField + 1) % 3
+		} else {
+			m.createFocusField = (m.createFocusField + 2) % 3
+		}
+		// Update focus state
+		if m.createFocusField == 0 {
+			m.createTicketInput.Focus()
+			m.createContextArea.Blur()
+		} else if m.createFocusField == 1 {
+			m.createTicketInput.Blur()
+			m.createContextArea.Blur()
+		} else {
+			m.createTicketInput.Blur()
+			m.createContextArea.Focus()
+		}
+		return m, nil
+	}
+
+	// Route input to focused field
+	if m.createFocusField == 0 {
+		m.createTicketInput, cmd = m.createTicketInput.Update(msg)
+		return m, cmd
+	} else if m.createFocusField == 1 {
+		// Skill field: space or arrows cycle options
+		if keyStr == " " || keyStr == "left" || keyStr == "right" {
+			m.createSkill = (m.createSkill + 1) % len(skillOptions)
+		}
+		return m, nil
+	}
+	m.createContextArea, cmd = m.createContextArea.Update(msg)
+	return m, cmd
+}
+
+// handleResetFormKey handles input for the hard reset form
+func (m model) handleResetFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.resettingInProgress {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, keys.Escape):
+		m.closeModal(modalReset)
+		m.resetError = ""
+		return m, nil
+
+	case key.Matches(msg, keys.Save), key.Matches(msg, keys.Enter):
+		inv := m.getSelectedInvestigation()
+		if inv != nil {
+			m.resettingInProgress = true
+			m.resetError = ""
+			return m, hardResetCmd(inv.ID, strings.TrimSpace(m.resetContextArea.Value()))
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.resetContextArea, cmd = m.resetContextArea.Update(msg)
+	return m, cmd
+}
+
+// handleReplyPromptKey handles input for the customer reply prompt
+func (m model) handleReplyPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.approvingReply {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, keys.Escape), key.Matches(msg, keys.No):
+		inv := m.getSelectedInvestigation()
+		if inv != nil {
+			return m, dismissReplyCmd(inv.ID)
+		}
+		m.closeModal(modalReply)
+		return m, nil
+
+	case key.Matches(msg, keys.Yes), key.Matches(msg, keys.Enter):
+		inv := m.getSelectedInvestigation()
+		if inv != nil {
+			m.approvingReply = true
+			m.replyError = ""
+			return m, approveNewRunCmd(inv.ID, strings.TrimSpace(m.replyContextArea.Value()))
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.replyContextArea, cmd = m.replyContextArea.Update(msg)
+	return m, cmd
+}
+
 // isShowingCP1Review returns true when the checkpoint 1 review card should be shown
 func (m model) isShowingCP1Review() bool {
 	inv := m.getSelectedInvestigation()
@@ -904,22 +1573,7 @@ func (m model) handleCP1Key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.selectedIndex++
 			m.cp1Loaded = 0
 		}
-		inv := m.getSelectedInvestigation()
-		if inv != nil {
-			cmds := []tea.Cmd{
-				loadAgentStatusesCmd(inv.ID),
-				streamAgentLogsCmd(inv.ID, m.getActiveAgentName()),
-				loadAgentFindingsCmd(inv.ID, m.getActiveAgentName()),
-			}
-			if inv.Status == "waiting" && inv.CurrentCheckpoint == "checkpoint_1_post_classification" {
-				cmds = append(cmds, loadTicketDataCmd(inv.ID))
-			}
-			if inv.Status == "complete" {
-				cmds = append(cmds, loadPhase1FindingsCmd(inv.ID))
-			}
-			return m, tea.Batch(cmds...)
-		}
-		return m, nil
+		return m, m.selectInvestigation()
 	case key.Matches(msg, keys.Enter):
 		// Open dropdown for focused field
 		m.cp1DropdownOpen = true
@@ -968,7 +1622,7 @@ func (m model) handleCP1Key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, approveCheckpointCmd(inv.ID, inv.CurrentCheckpoint)
 
 	case key.Matches(msg, keys.Refresh):
-		return m, loadInvestigationsCmd()
+		return m, m.investigationsCmd()
 
 	case key.Matches(msg, keys.Debug):
 		m.showDebugOverlay = !m.showDebugOverlay
@@ -1007,6 +1661,37 @@ func (m model) cp1CurrentValue() string {
 }
 
 func main() {
+	ascii := flag.Bool("ascii", false, "render with ASCII glyphs instead of emoji")
+	emoji := flag.Bool("emoji", false, "force emoji glyphs even if the terminal looks constrained")
+	publisherName := flag.String("publisher", os.Getenv("TUI_PUBLISHER"), "where posted responses go: api or dry-run")
+	notifyPath := flag.String("notify-config", notifyConfigPath(), "notification rules (JSON); a missing file uses the defaults")
+	stateDir := flag.String("state-dir", defaultStateDir(), "where unsaved drafts and the response edit dataset are kept")
+	flag.StringVar(&snippetsDir, "snippets-dir", snippetsDir, "directory of response snippets (Markdown files)")
+	flag.Parse()
+	draftsDir = filepath.Join(*stateDir, "drafts")
+	learningDir = filepath.Join(*stateDir, "learning")
+
+	pub, err := newPublisher(*publisherName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	publisher = pub
+
+	if notifyConfig, err = loadNotifyConfig(*notifyPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	switch {
+	case *ascii:
+		setASCIIMode(true)
+	case *emoji:
+		setASCIIMode(false)
+	default:
+		setASCIIMode(detectASCIIMode())
+	}
+
 	// Clear scrollback buffer before entering alt screen
 	// ESC[3J clears scrollback, ESC[2J clears screen, ESC[H moves cursor home
 	fmt.Print("\033[3J\033[2J\033[H")
@@ -1016,8 +1701,14 @@ func main() {
 		tea.WithAltScreen(),
 	)
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if err != nil {
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
 	}
+	if m, ok := final.(model); ok {
+		if err := m.flushDrafts(); err != nil {
+			fmt.Fprintln(os.Stderr, "autosave:", err)
+		}
+	}
 }
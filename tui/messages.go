@@ -1,28 +1,37 @@
 package main
 
-import "time"
+import (
+	"time"
 
-// Message types for async updates
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Message types for async updates. Load results carry the generation they
+// were issued with so out-of-order replies can be dropped (see loadseq.go).
 
 type investigationsLoadedMsg struct {
 	investigations []Investigation
+	gen            int
 }
 
 type agentStatusesLoadedMsg struct {
 	investigationID int
 	agents          map[string]*AgentState
+	gen             int
 }
 
 type agentLogsLoadedMsg struct {
 	investigationID int
 	agentName       string
 	logs            []LogEntry
+	gen             int
 }
 
 type agentFindingsLoadedMsg struct {
 	investigationID int
 	agentName       string
 	findings        []Finding
+	gen             int
 }
 
 type checkpointApprovedMsg struct {
@@ -32,11 +41,14 @@ type checkpointApprovedMsg struct {
 type summaryLoadedMsg struct {
 	investigationID int
 	summary         *InvestigationSummary
+	gen             int
 }
 
 type customerResponseLoadedMsg struct {
 	investigationID int
 	response        *CustomerResponse
+	disk            diskState
+	gen             int
 }
 
 type responseEditedMsg struct {
@@ -46,14 +58,27 @@ type responseEditedMsg struct {
 
 type responseCopiedMsg struct {
 	investigationID int
+	method          string // Clipboard tool or OSC 52, see clipboard.go
+}
+
+// textCopiedMsg reports a copy from selection mode
+type textCopiedMsg struct {
+	what   string // e.g. "Slack findings item 2 as markdown"
+	method string
 }
 
 type responseSavedMsg struct {
 	investigationID int
+	content         string
+	disk            diskState
 }
 
 type responsePostedMsg struct {
 	investigationID int
+	content         string // What was posted
+	receipt         PublishReceipt
+	err             error // Post failed; nothing was published
+	recordErr       error // Post succeeded but the receipt couldn't be saved
 }
 
 type investigationCreatedMsg struct {
@@ -64,6 +89,7 @@ type investigationCreatedMsg struct {
 type ticketDataLoadedMsg struct {
 	investigationID int
 	data            *TicketData
+	gen             int
 }
 
 type investigationUpdatedMsg struct {
@@ -74,6 +100,7 @@ type investigationUpdatedMsg struct {
 type phase1FindingsLoadedMsg struct {
 	investigationID int
 	content         string
+	gen             int
 }
 
 type hardResetCompletedMsg struct {
@@ -93,13 +120,199 @@ type replyDismissedMsg struct {
 	err             error
 }
 
-type errMsg struct {
+// linearDraftLoadedMsg carries a nil draft when the file doesn't exist
+type linearDraftLoadedMsg struct {
+	investigationID int
+	draft           *LinearDraft
+	disk            diskState
+	gen             int
+}
+
+type linearDraftSavedMsg struct {
+	investigationID int
+	draft           *LinearDraft
+	disk            diskState
+	err             error
+}
+
+type linearIssueFiledMsg struct {
+	investigationID int
+	identifier      string
+	url             string
+	err             error
+}
+
+// kbArticleLoadedMsg carries a nil article when there's no kb-article.md
+type kbArticleLoadedMsg struct {
+	investigationID int
+	article         *KBArticle
+	disk            diskState
+	gen             int
+}
+
+// versionsLoadedMsg carries the investigation's snapshots, newest first
+type versionsLoadedMsg struct {
+	investigationID int
+	versions        []InvestigationVersion
+	err             error
+	gen             int
+}
+
+// versionRestoredMsg reports a rollback; message is the server's summary
+type versionRestoredMsg struct {
+	investigationID int
+	message         string
+	err             error
+}
+
+type kbArticleSavedMsg struct {
+	investigationID int
+	article         *KBArticle
+	disk            diskState
+}
+
+// saveConflictMsg is sent instead of a saved message when the file changed
+// on disk since editing started
+type saveConflictMsg struct {
+	conflict *saveConflict
+}
+
+type kbExportedMsg struct {
+	investigationID int
+	path            string
+	err             error
+}
+
+type kbGeneratedMsg struct {
+	investigationID int
+	err             error
+}
+
+type metricsLoadedMsg struct {
+	report *metricsReport
+}
+
+// outwardRefusedMsg means an approved outward action was not attempted
+type outwardRefusedMsg struct {
 	err error
 }
 
+type settingsLoadedMsg struct {
+	settings *Settings
+	err      error
+}
+
+// agentStoppedMsg reports a stop request
+type agentStoppedMsg struct {
+	investigationID int
+	agentName       string
+	err             error
+}
+
+// agentRetriedMsg reports one agent's retry request
+type agentRetriedMsg struct {
+	investigationID int
+	agentName       string
+	err             error
+}
+
+// procSampledMsg carries one /proc reading per agent PID
+type procSampledMsg struct {
+	samples map[int]procSample
+}
+
+// watchdogCheckedMsg carries the health of every running investigation
+type watchdogCheckedMsg struct {
+	reports   map[int]*investigationHealth
+	checkedAt time.Time
+}
+
+// investigationRetriedMsg means the server accepted a retry
+type investigationRetriedMsg struct {
+	investigationID int
+}
+
+// settingsDocLoadedMsg carries the full settings document for the editor
+type settingsDocLoadedMsg struct {
+	doc jsonObject
+	err error
+}
+
+// settingsSavedMsg reports a PUT /api/settings; settings is the saved
+// document reparsed for the rest of the TUI.
+type settingsSavedMsg struct {
+	settings *Settings
+	err      error
+}
+
+// externalEditDoneMsg is sent when $EDITOR exits. content is the edited
+// document; err is set when the editor failed or the file couldn't be read.
+type externalEditDoneMsg struct {
+	doc     externalEdit
+	content string
+	err     error
+}
+
+// draftsAutosavedMsg reports a background autosave
+type draftsAutosavedMsg struct {
+	err error
+}
+
+// draftsRecoveredMsg carries drafts left over from a previous session
+type draftsRecoveredMsg struct {
+	drafts []savedDraft
+	err    error
+}
+
+// snippetsLoadedMsg carries the snippet library
+type snippetsLoadedMsg struct {
+	snippets []Snippet
+	err      error // Files that couldn't be read; the rest are still loaded
+}
+
+// snippetSavedMsg reports a snippet created from selected text
+type snippetSavedMsg struct {
+	name string
+	path string
+	err  error
+}
+
+// editCapturedMsg reports a failure to record the edit dataset
+type editCapturedMsg struct {
+	err error
+}
+
+// editInsightsLoadedMsg carries the edit dataset summary
+type editInsightsLoadedMsg struct {
+	report *editInsights
+}
+
+// errMsg reports a failed command. source names the command for the error
+// history and retry, when set, re-runs it. Only fatal errors block the UI;
+// everything else becomes a toast (see toast.go).
+type errMsg struct {
+	err    error
+	source string
+	retry  tea.Cmd
+	fatal  bool
+	poll   bool // From a background load; repeats stay quiet until it succeeds
+}
+
 func (e errMsg) Error() string {
 	return e.err.Error()
 }
 
+// reloadMsg asks Update to reissue a failed load under a new generation
+type reloadMsg struct {
+	kind            loadKind
+	investigationID int
+	agentName       string
+}
+
+// toastExpiredMsg removes a toast once its display time is up
+type toastExpiredMsg struct {
+	id int
+}
+
 // tickMsg is sent periodically to trigger refresh of running investigations
 type tickMsg time.Time
@@ -0,0 +1,534 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// metricsPhases are the phases the runner writes phaseN_duration_ms for,
+// with the checkpoint review that follows each
+var metricsPhases = []struct {
+	key        string
+	label      string
+	checkpoint string
+}{
+	{"phase0", "Phase 0 classification", "Classification review"},
+	{"phase1", "Phase 1 context", "Context review"},
+	{"phase2", "Phase 2 synthesis", "Document review"},
+}
+
+// throughputDays is how far back the per-day charts go
+const throughputDays = 14
+
+// AdminStats is the part of /api/admin/stats the dashboard shows
+type AdminStats struct {
+	Investigations struct {
+		Total     int            `json:"total"`
+		Active    int            `json:"active"`
+		Completed int            `json:"completed"`
+		ByStatus  map[string]int `json:"byStatus"`
+	} `json:"investigations"`
+}
+
+// durationStat summarizes one set of durations
+type durationStat struct {
+	label   string
+	samples []time.Duration // In investigation order, for the sparkline
+	median  time.Duration
+	p90     time.Duration
+}
+
+type countStat struct {
+	label  string
+	count  int
+	median time.Duration // Median total phase time, 0 when unknown
+}
+
+// metricsReport is everything the dashboard draws, computed off the UI
+// goroutine from the investigations list and each investigation's files.
+type metricsReport struct {
+	generated        time.Time
+	investigations   int
+	withMetrics      int
+	phases           []durationStat
+	waits            []durationStat
+	days             []time.Time
+	createdPerDay    []int
+	completedPerDay  []int
+	byClassification []countStat
+	byProductArea    []countStat
+	stats            *AdminStats
+	statsErr         error
+}
+
+// Build the dashboard report. Missing files just leave gaps; the admin stats
+// call is best-effort so the dashboard still works without the server.
+func loadMetricsCmd(investigations []Investigation) tea.Cmd {
+	return func() tea.Msg {
+		report := buildMetricsReport(investigations, time.Now())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		var stats AdminStats
+		if err := api.get(ctx, "/api/admin/stats", &stats); err != nil {
+			report.statsErr = err
+		} else {
+			report.stats = &stats
+		}
+		return metricsLoadedMsg{report: report}
+	}
+}
+
+func buildMetricsReport(investigations []Investigation, now time.Time) *metricsReport {
+	report := &metricsReport{generated: now, investigations: len(investigations)}
+
+	// Sorted by ID so sparklines read oldest to newest
+	invs := append([]Investigation(nil), investigations...)
+	sort.Slice(invs, func(i, j int) bool { return invs[i].ID < invs[j].ID })
+
+	phaseSamples := make(map[string][]time.Duration)
+	waitSamples := make(map[string][]time.Duration)
+	totals := make(map[int]time.Duration)
+	for _, inv := range invs {
+		if phases := readPhaseDurations(inv.ID); len(phases) > 0 {
+			report.withMetrics++
+			for phase, d := range phases {
+				phaseSamples[phase] = append(phaseSamples[phase], d)
+				totals[inv.ID] += d
+			}
+		}
+		for checkpoint, d := range readCheckpointWaits(inv.ID) {
+			waitSamples[checkpoint] = append(waitSamples[checkpoint], d)
+		}
+	}
+
+	for _, phase := range metricsPhases {
+		report.phases = append(report.phases, newDurationStat(phase.label, phaseSamples[phase.key]))
+	}
+	for _, phase := range metricsPhases {
+		if samples := waitSamples[phase.key]; len(samples) > 0 {
+			report.waits = append(report.waits, newDurationStat(phase.checkpoint, samples))
+		}
+	}
+
+	// Throughput: created and completed per day over the last two weeks.
+	// Completion uses updated_at, the last change to a finished investigation.
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for i := throughputDays - 1; i >= 0; i-- {
+		report.days = append(report.days, today.AddDate(0, 0, -i))
+	}
+	report.createdPerDay = make([]int, throughputDays)
+	report.completedPerDay = make([]int, throughputDays)
+	dayIndex := func(ts string) int {
+		t, err := parseServerTime(ts)
+		if err != nil {
+			return -1
+		}
+		t = t.In(now.Location())
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+		idx := throughputDays - 1 - int(today.Sub(day).Hours()/24)
+		if idx < 0 || idx >= throughputDays {
+			return -1
+		}
+		return idx
+	}
+	for _, inv := range invs {
+		if i := dayIndex(inv.CreatedAt); i >= 0 {
+			report.createdPerDay[i]++
+		}
+		if inv.Status == "complete" {
+			if i := dayIndex(inv.UpdatedAt); i >= 0 {
+				report.completedPerDay[i]++
+			}
+		}
+	}
+
+	report.byClassification = breakdown(invs, totals, func(inv Investigation) string { return inv.Classification })
+	report.byProductArea = breakdown(invs, totals, func(inv Investigation) string { return inv.ProductArea })
+	return report
+}
+
+// readPhaseDurations reads phaseN_duration_ms from metrics.json
+func readPhaseDurations(investigationID int) map[string]time.Duration {
+	path := resolveInvestigationFile(investigationID, "metrics.json")
+	if path == "" {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil
+	}
+	phases := make(map[string]time.Duration)
+	for k, v := range raw {
+		ms, ok := v.(float64)
+		if !ok || !strings.HasSuffix(k, "_duration_ms") {
+			continue
+		}
+		phases[strings.TrimSuffix(k, "_duration_ms")] = time.Duration(ms) * time.Millisecond
+	}
+	return phases
+}
+
+// readCheckpointWaits measures how long each checkpoint waited for a human:
+// from a phaseN "complete" event in activity-log.jsonl to the phase(N+1)
+// "start". Sub-phase tags (phase1-pylon) and outward actions (respond,
+// linear-issue, kb-article) aren't checkpoints and are skipped. Checkpoints
+// still waiting are left out.
+func readCheckpointWaits(investigationID int) map[string]time.Duration {
+	path := resolveInvestigationFile(investigationID, "activity-log.jsonl")
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	waits := make(map[string]time.Duration)
+	completedPhase := -1
+	var completedAt time.Time
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event struct {
+			TS    time.Time `json:"ts"`
+			Phase string    `json:"phase"`
+			Type  string    `json:"type"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &event) != nil {
+			continue
+		}
+		phase, ok := topLevelPhase(event.Phase)
+		if !ok {
+			continue
+		}
+		switch event.Type {
+		case "complete":
+			completedPhase, completedAt = phase, event.TS
+		case "start":
+			if completedPhase >= 0 && phase == completedPhase+1 && event.TS.After(completedAt) {
+				// A re-run keeps the latest wait for the checkpoint
+				waits[fmt.Sprintf("phase%d", completedPhase)] = event.TS.Sub(completedAt)
+			}
+			completedPhase = -1
+		}
+	}
+	return waits
+}
+
+// topLevelPhase parses a "phaseN" activity tag
+func topLevelPhase(tag string) (int, bool) {
+	rest, ok := strings.CutPrefix(tag, "phase")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(rest)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// parseServerTime reads the server's "2006-01-02 15:04:05" UTC timestamps
+func parseServerTime(ts string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, ts); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02 15:04:05", ts)
+}
+
+func newDurationStat(label string, samples []time.Duration) durationStat {
+	stat := durationStat{label: label, samples: samples}
+	if len(samples) == 0 {
+		return stat
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	stat.median = percentile(sorted, 50)
+	stat.p90 = percentile(sorted, 90)
+	return stat
+}
+
+// percentile uses the nearest-rank method on sorted samples
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func breakdown(invs []Investigation, totals map[int]time.Duration, field func(Investigation) string) []countStat {
+	counts := make(map[string]int)
+	durations := make(map[string][]time.Duration)
+	for _, inv := range invs {
+		label := strings.TrimSpace(field(inv))
+		if label == "" {
+			label = "(not set)"
+		}
+		counts[label]++
+		if d, ok := totals[inv.ID]; ok {
+			durations[label] = append(durations[label], d)
+		}
+	}
+	var stats []countStat
+	for label, count := range counts {
+		stat := countStat{label: label, count: count}
+		if ds := durations[label]; len(ds) > 0 {
+			stat.median = newDurationStat(label, ds).median
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].count != stats[j].count {
+			return stats[i].count > stats[j].count
+		}
+		return stats[i].label < stats[j].label
+	})
+	return stats
+}
+
+// sparkline draws one character per value, scaled to the largest
+func sparkline(values []float64) string {
+	levels := []rune("▁▂▃▄▅▆▇█")
+	if asciiMode {
+		levels = []rune("_.-:=+*#")
+	}
+	maxValue := 0.0
+	for _, v := range values {
+		maxValue = math.Max(maxValue, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if maxValue > 0 {
+			idx = int(math.Round(v / maxValue * float64(len(levels)-1)))
+		}
+		b.WriteRune(levels[idx])
+	}
+	return b.String()
+}
+
+// bar draws a horizontal bar of up to width cells
+func bar(value, maxValue float64, width int) string {
+	full, half := "█", "▌"
+	if asciiMode {
+		full, half = "#", "+"
+	}
+	if maxValue <= 0 || width <= 0 {
+		return ""
+	}
+	cells := value / maxValue * float64(width)
+	n := int(cells)
+	s := strings.Repeat(full, n)
+	if cells-float64(n) >= 0.5 {
+		s += half
+	}
+	return s
+}
+
+func durationSeconds(ds []time.Duration) []float64 {
+	values := make([]float64, len(ds))
+	for i, d := range ds {
+		values[i] = d.Seconds()
+	}
+	return values
+}
+
+func intsToFloats(ns []int) []float64 {
+	values := make([]float64, len(ns))
+	for i, n := range ns {
+		values[i] = float64(n)
+	}
+	return values
+}
+
+// formatDuration keeps durations short: 42s, 3m05s, 2h10m, 3d4h
+func formatDuration(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "—"
+	case d < time.Minute:
+		return fmt.Sprintf("%.0fs", d.Seconds())
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// openMetrics shows the dashboard and (re)builds its report
+func (m *model) openMetrics() tea.Cmd {
+	m.pushModal(modalMetrics)
+	m.metricsLoading = true
+	return loadMetricsCmd(m.investigations)
+}
+
+func (m model) handleMetricsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Escape), key.Matches(msg, keys.Metrics), key.Matches(msg, keys.Quit):
+		m.closeModal(modalMetrics)
+		return m, nil
+	case key.Matches(msg, keys.Refresh):
+		return m, m.openMetrics()
+	}
+	return m, nil
+}
+
+func (m model) renderMetricsDashboard() string {
+	dialogWidth := m.modalWidth(110)
+	dialogHeight := m.modalHeight(38)
+	inner := dialogWidth - 6
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(c1Primary).Render("Investigation Metrics")
+	report := m.metrics
+	var body string
+	switch {
+	case report == nil:
+		body = fmt.Sprintf("%s Reading metrics...", m.spinner.View())
+	default:
+		body = renderMetricsReport(report, inner)
+	}
+
+	status := ""
+	if report != nil {
+		status = "updated " + report.generated.Format("15:04:05")
+		if m.metricsLoading {
+			status = m.spinner.View() + " refreshing"
+		}
+	}
+	header := title + "  " + dimmedTextStyle.Render(status)
+	footer := dimmedTextStyle.Render("r: refresh • Esc: close")
+
+	content := lipgloss.NewStyle().Height(dialogHeight - 6).MaxHeight(dialogHeight - 6).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", body))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(c1Primary).
+		BorderBackground(bgPrimary).
+		Background(bgPrimary).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(lipgloss.JoinVertical(lipgloss.Left, content, footer))
+}
+
+func renderMetricsReport(r *metricsReport, width int) string {
+	heading := lipgloss.NewStyle().Bold(true).Foreground(textPrimary)
+	muted := lipgloss.NewStyle().Foreground(textMuted)
+	accent := lipgloss.NewStyle().Foreground(c1Primary)
+	var lines []string
+
+	// Totals, from the server when it's up
+	if r.stats != nil {
+		s := r.stats.Investigations
+		lines = append(lines, fmt.Sprintf("%d investigations • %d active • %d complete", s.Total, s.Active, s.Completed))
+		var statuses []string
+		for status, n := range s.ByStatus {
+			statuses = append(statuses, fmt.Sprintf("%s %d", status, n))
+		}
+		sort.Strings(statuses)
+		if len(statuses) > 0 {
+			lines = append(lines, muted.Render("By status: "+strings.Join(statuses, " • ")))
+		}
+	} else {
+		lines = append(lines, fmt.Sprintf("%d investigations", r.investigations),
+			muted.Render("Server stats unavailable: "+truncateStr(errString(r.statsErr), width-28)))
+	}
+	lines = append(lines, "")
+
+	// Phase durations
+	lines = append(lines, heading.Render(fmt.Sprintf("PHASE DURATIONS (%d with metrics.json)", r.withMetrics)))
+	lines = append(lines, muted.Render(fmt.Sprintf("%-26s %4s %8s %8s  %s", "", "n", "median", "p90", "per investigation")))
+	for _, s := range r.phases {
+		lines = append(lines, fmt.Sprintf("%-26s %4d %8s %8s  %s",
+			s.label, len(s.samples), formatDuration(s.median), formatDuration(s.p90),
+			accent.Render(sparkline(durationSeconds(s.samples)))))
+	}
+	lines = append(lines, "")
+
+	// Checkpoint waits
+	lines = append(lines, heading.Render("CHECKPOINT WAIT (phase complete → next phase start)"))
+	if len(r.waits) == 0 {
+		lines = append(lines, muted.Render("No finished checkpoints yet"))
+	}
+	for _, s := range r.waits {
+		lines = append(lines, fmt.Sprintf("%-26s %4d %8s %8s  %s",
+			s.label, len(s.samples), formatDuration(s.median), formatDuration(s.p90),
+			accent.Render(sparkline(durationSeconds(s.samples)))))
+	}
+	lines = append(lines, "")
+
+	// Throughput
+	lines = append(lines, heading.Render(fmt.Sprintf("THROUGHPUT (last %d days, %s → %s)",
+		throughputDays, r.days[0].Format("Jan 2"), r.days[len(r.days)-1].Format("Jan 2"))))
+	lines = append(lines,
+		fmt.Sprintf("%-10s %s  %d total", "Created", accent.Render(sparkline(intsToFloats(r.createdPerDay))), sum(r.createdPerDay)),
+		fmt.Sprintf("%-10s %s  %d total", "Completed", accent.Render(sparkline(intsToFloats(r.completedPerDay))), sum(r.completedPerDay)),
+		"")
+
+	// Breakdowns side by side when there's room
+	barWidth := 12
+	left := renderBreakdown("BY CLASSIFICATION", r.byClassification, barWidth)
+	right := renderBreakdown("BY PRODUCT AREA", r.byProductArea, barWidth)
+	if lipgloss.Width(left)+lipgloss.Width(right)+4 <= width {
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, left, "    ", right))
+	} else {
+		lines = append(lines, left, "", right)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderBreakdown(title string, stats []countStat, barWidth int) string {
+	heading := lipgloss.NewStyle().Bold(true).Foreground(textPrimary)
+	accent := lipgloss.NewStyle().Foreground(c1Primary)
+	lines := []string{heading.Render(title)}
+	if len(stats) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, lines[0], dimmedTextStyle.Render("No investigations"))
+	}
+	maxCount := float64(stats[0].count)
+	for _, s := range stats {
+		median := ""
+		if s.median > 0 {
+			median = "  med " + formatDuration(s.median)
+		}
+		b := bar(float64(s.count), maxCount, barWidth)
+		b += strings.Repeat(" ", barWidth-lipgloss.Width(b))
+		lines = append(lines, fmt.Sprintf("%-18s %s %2d%s", truncateStr(s.label, 18), accent.Render(b), s.count, median))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func sum(ns []int) int {
+	total := 0
+	for _, n := range ns {
+		total += n
+	}
+	return total
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
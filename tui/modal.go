@@ -0,0 +1,390 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// modalKind identifies a dialog that can be stacked over the main layout
+type modalKind int
+
+const (
+	modalNone modalKind = iota
+	modalConfirm
+	modalCreate
+	modalReset
+	modalReply
+	modalErrors
+	modalApproval
+	modalMetrics
+	modalSettings
+	modalAgentRetry
+	modalConflict
+	modalRecovery
+	modalSnippets
+	modalNewSnippet
+	modalEditInsights
+	modalPalette
+)
+
+func (k modalKind) String() string {
+	switch k {
+	case modalConfirm:
+		return "confirm"
+	case modalCreate:
+		return "create"
+	case modalReset:
+		return "reset"
+	case modalReply:
+		return "reply"
+	case modalErrors:
+		return "errors"
+	case modalApproval:
+		return "approval"
+	case modalMetrics:
+		return "metrics"
+	case modalSettings:
+		return "settings"
+	case modalAgentRetry:
+		return "agent retry"
+	case modalConflict:
+		return "conflict"
+	case modalRecovery:
+		return "recovery"
+	case modalSnippets:
+		return "snippets"
+	case modalNewSnippet:
+		return "new snippet"
+	case modalEditInsights:
+		return "edit insights"
+	case modalPalette:
+		return "palette"
+	default:
+		return "none"
+	}
+}
+
+// topModal returns the dialog that currently owns the keyboard
+func (m model) topModal() modalKind {
+	if len(m.modals) == 0 {
+		return modalNone
+	}
+	return m.modals[len(m.modals)-1]
+}
+
+func (m model) isModalOpen(kind modalKind) bool {
+	for _, k := range m.modals {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// pushModal opens a dialog on top of the stack. Re-opening a dialog that is
+// already on the stack moves it to the top instead of duplicating it.
+func (m *model) pushModal(kind modalKind) {
+	m.closeModal(kind)
+	m.modals = append(m.modals, kind)
+}
+
+// closeModal removes a dialog wherever it sits in the stack
+func (m *model) closeModal(kind modalKind) {
+	kept := make([]modalKind, 0, len(m.modals))
+	for _, k := range m.modals {
+		if k != kind {
+			kept = append(kept, k)
+		}
+	}
+	m.modals = kept
+	if kind == modalConfirm {
+		m.confirmDiff = nil
+	}
+}
+
+// handleModalKey routes a key press to the top-most dialog. Dialogs lower in
+// the stack never see input until everything above them is closed.
+func (m model) handleModalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch m.topModal() {
+	case modalConfirm:
+		return m.handleConfirmKey(msg)
+	case modalCreate:
+		return m.handleCreateFormKey(msg)
+	case modalReset:
+		return m.handleResetFormKey(msg)
+	case modalReply:
+		return m.handleReplyPromptKey(msg)
+	case modalErrors:
+		return m.handleErrorPanelKey(msg)
+	case modalApproval:
+		return m.handleApprovalKey(msg)
+	case modalMetrics:
+		return m.handleMetricsKey(msg)
+	case modalSettings:
+		return m.handleSettingsKey(msg)
+	case modalAgentRetry:
+		return m.handleAgentRetryKey(msg)
+	case modalConflict:
+		return m.handleConflictKey(msg)
+	case modalRecovery:
+		return m.handleRecoveryKey(msg)
+	case modalSnippets:
+		return m.handleSnippetPickerKey(msg)
+	case modalNewSnippet:
+		return m.handleNewSnippetKey(msg)
+	case modalEditInsights:
+		return m.handleEditInsightsKey(msg)
+	case modalPalette:
+		return m.handlePaletteKey(msg)
+	}
+	return m, nil
+}
+
+func (m model) renderModal(kind modalKind) string {
+	switch kind {
+	case modalConfirm:
+		return m.renderConfirmDialog()
+	case modalCreate:
+		return m.renderCreateForm()
+	case modalReset:
+		return m.renderResetForm()
+	case modalReply:
+		return m.renderReplyPrompt()
+	case modalErrors:
+		return m.renderErrorPanel()
+	case modalApproval:
+		return m.renderApprovalDialog()
+	case modalMetrics:
+		return m.renderMetricsDashboard()
+	case modalSettings:
+		return m.renderSettingsEditor()
+	case modalAgentRetry:
+		return m.renderAgentRetryDialog()
+	case modalConflict:
+		return m.renderConflictDialog()
+	case modalRecovery:
+		return m.renderRecoveryDialog()
+	case modalSnippets:
+		return m.renderSnippetPicker()
+	case modalNewSnippet:
+		return m.renderNewSnippetDialog()
+	case modalEditInsights:
+		return m.renderEditInsights()
+	case modalPalette:
+		return m.renderPalette()
+	default:
+		return ""
+	}
+}
+
+// modalWidth clamps a dialog's preferred width to the terminal, leaving a
+// margin so the dimmed layout stays visible around it.
+func (m model) modalWidth(preferred int) int {
+	w := preferred
+	if w > m.width-6 {
+		w = m.width - 6
+	}
+	if w < 30 {
+		w = 30
+	}
+	return w
+}
+
+// modalHeight clamps a dialog's preferred height the same way
+func (m model) modalHeight(preferred int) int {
+	h := preferred
+	if h > m.height-6 {
+		h = m.height - 6
+	}
+	if h < 5 {
+		h = 5
+	}
+	return h
+}
+
+// compositeModals draws the modal stack over an already rendered frame.
+// The frame and every dialog below the top one are flattened and dimmed so
+// only the active dialog keeps its colors.
+func (m model) compositeModals(frame string) string {
+	if len(m.modals) == 0 {
+		return frame
+	}
+
+	base := plainLines(frame, m.width)
+	for _, kind := range m.modals[:len(m.modals)-1] {
+		base = overlayPlain(base, plainLines(m.renderModal(kind), 0), m.width)
+	}
+
+	top := m.renderModal(m.topModal())
+	topLines := strings.Split(top, "\n")
+	topWidth := lipgloss.Width(top)
+	x, y := centerOffset(m.width, len(base), topWidth, len(topLines))
+
+	out := make([]string, len(base))
+	for i, line := range base {
+		row := i - y
+		if row < 0 || row >= len(topLines) {
+			out[i] = modalBackdropStyle.Render(line)
+			continue
+		}
+		left, right := cutCells(line, x, x+topWidth)
+		fg := topLines[row]
+		if pad := topWidth - lipgloss.Width(fg); pad > 0 {
+			fg += strings.Repeat(" ", pad)
+		}
+		out[i] = modalBackdropStyle.Render(left) + fg + modalBackdropStyle.Render(right)
+	}
+	return strings.Join(out, "\n")
+}
+
+// overlayPlain centers unstyled fg lines over unstyled bg lines
+func overlayPlain(bg, fg []string, width int) []string {
+	fgWidth := 0
+	for _, line := range fg {
+		if w := runewidth.StringWidth(line); w > fgWidth {
+			fgWidth = w
+		}
+	}
+	x, y := centerOffset(width, len(bg), fgWidth, len(fg))
+
+	out := append([]string(nil), bg...)
+	for i, line := range fg {
+		row := y + i
+		if row < 0 || row >= len(out) {
+			continue
+		}
+		left, right := cutCells(out[row], x, x+fgWidth)
+		out[row] = left + runewidth.FillRight(line, fgWidth) + right
+	}
+	return out
+}
+
+func centerOffset(outerW, outerH, innerW, innerH int) (int, int) {
+	x := (outerW - innerW) / 2
+	y := (outerH - innerH) / 2
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return x, y
+}
+
+// cutCells splits an unstyled line around the cell range [from, to).
+// Wide runes straddling either edge are replaced by spaces so the pieces
+// still add up to the original width.
+func cutCells(line string, from, to int) (string, string) {
+	var left, right strings.Builder
+	col := 0
+	for _, r := range line {
+		w := runewidth.RuneWidth(r)
+		switch {
+		case col+w <= from:
+			left.WriteRune(r)
+		case col < from:
+			left.WriteString(strings.Repeat(" ", from-col))
+		case col >= to:
+			right.WriteRune(r)
+		case col+w > to:
+			right.WriteString(strings.Repeat(" ", col+w-to))
+		}
+		col += w
+	}
+	return left.String(), right.String()
+}
+
+// overlayStyled draws a styled block over styled lines at (x, y) without
+// flattening the lines underneath (used for toasts).
+func overlayStyled(bg []string, fg string, x, y int) []string {
+	fgLines := strings.Split(fg, "\n")
+	fgWidth := lipgloss.Width(fg)
+	if x < 0 {
+		x = 0
+	}
+
+	out := append([]string(nil), bg...)
+	for i, line := range fgLines {
+		row := y + i
+		if row < 0 || row >= len(out) {
+			continue
+		}
+		left, right := cutStyled(out[row], x, x+fgWidth)
+		if pad := x - lipgloss.Width(left); pad > 0 {
+			left += strings.Repeat(" ", pad)
+		}
+		if pad := fgWidth - lipgloss.Width(line); pad > 0 {
+			line += strings.Repeat(" ", pad)
+		}
+		out[row] = left + line + right
+	}
+	return out
+}
+
+// cutStyled is cutCells for lines that still carry escape sequences. Styling
+// active at the cut is replayed at the start of the right half so colors
+// continue past the overlay.
+func cutStyled(line string, from, to int) (string, string) {
+	var left, right, active strings.Builder
+	col := 0
+	rightStarted := false
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			if loc := ansiSequence.FindStringIndex(line[i:]); loc != nil && loc[0] == 0 {
+				seq := line[i : i+loc[1]]
+				active.WriteString(seq)
+				if col < from {
+					left.WriteString(seq)
+				} else if rightStarted {
+					right.WriteString(seq)
+				}
+				i += loc[1]
+				continue
+			}
+		}
+
+		r, size := utf8.DecodeRuneInString(line[i:])
+		w := runewidth.RuneWidth(r)
+		switch {
+		case col+w <= from:
+			left.WriteRune(r)
+		case col < from:
+			left.WriteString(strings.Repeat(" ", from-col))
+		case col >= to:
+			if !rightStarted {
+				right.WriteString(active.String())
+				rightStarted = true
+			}
+			right.WriteRune(r)
+		case col+w > to:
+			right.WriteString(strings.Repeat(" ", col+w-to))
+		}
+		col += w
+		i += size
+	}
+	left.WriteString("\x1b[0m")
+	return left.String(), right.String()
+}
+
+var ansiSequence = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+// plainLines strips styling from a rendered block and pads each line to
+// width (when width > 0) so overlays can be cut at any column.
+func plainLines(s string, width int) []string {
+	lines := strings.Split(ansiSequence.ReplaceAllString(s, ""), "\n")
+	if width > 0 {
+		for i, line := range lines {
+			lines[i] = runewidth.FillRight(runewidth.Truncate(line, width, ""), width)
+		}
+	}
+	return lines
+}
@@ -26,6 +26,7 @@ const (
 	TabPylon
 	TabCodebase
 	TabSummary
+	TabLinearDraft
 	TabKB
 )
 
@@ -44,6 +45,7 @@ type Investigation struct {
 	NewReplySummary   string            `json:"new_reply_summary"`
 	CreatedAt         string            `json:"created_at"`
 	UpdatedAt         string            `json:"updated_at"`
+	LinearIssueID     string            `json:"linear_issue_id"`
 	AgentStatuses     map[string]string // agent_name -> status
 }
 
@@ -53,7 +55,10 @@ type AgentState struct {
 	Status     string // pending, running, completed, error, checkpoint
 	PID        int
 	StartedAt  time.Time
+	CompletedAt  time.Time
 	Runtime    time.Duration
+	RunNumber  int
+	ErrorMessage string // Set when Status is error
 	Logs       []LogEntry
 	Findings   []Finding
 	LogFile    string
@@ -90,6 +95,7 @@ type CustomerResponse struct {
 	EditedBy      string
 	CopiedToClip  bool
 	PostedToPylon bool
+	Posted        *PublishReceipt // Set once a publisher confirmed the post
 }
 
 // TicketData from ticket-data.json
@@ -119,9 +125,34 @@ type model struct {
 	// Agent data (investigation_id -> agent_name -> state)
 	agents map[int]map[string]*AgentState
 
+	// Selection mode (see selection.go)
+	selecting bool
+	selPane   int
+	selCursor int
+	selAnchor int // Range start, -1 when selecting a single item
+
+	// settings.json; nil until loaded, which makes every outward action
+	// require approval
+	settings *Settings
+
+	// Outward action approval (see safety.go)
+	outward               *outwardRequest
+	outwardMethod         string
+	outwardBusy           bool
+	approvalTicketInput   textinput.Model
+	approvalReviewerInput textinput.Model
+	approvalFocus         int // 0 = ticket number, 1 = reviewer
+	approvalError         string
+
+	// Load generations and the cancellable context for in-flight loads.
+	// Held by pointer so every copy of the model shares it (see loadseq.go).
+	loads *loadTracker
+
 	// Summary data (investigation_id -> summary/response)
 	summaries        map[int]*InvestigationSummary
 	customerResponses map[int]*CustomerResponse
+	responseLints     map[int][]lintFinding // Style check of each saved response
+	editLint          []lintFinding         // Style check of the response being edited
 
 	// UI components
 	findingsViewport  viewport.Model
@@ -136,9 +167,19 @@ type model struct {
 	loading           bool
 	ready             bool // Viewports ready
 	editingResponse   bool
-	showConfirmDialog bool
-	confirmAction     string // "post" or "save"
+	confirmAction     string // e.g. "save", "save_kb", "discard_draft"; see handleConfirmKey
 	confirmMessage    string
+	confirmDiff       []diffLine // Changes being confirmed, shown under the message
+
+	// Dialogs stacked over the main layout, bottom first (see modal.go)
+	modals []modalKind
+
+	// Toasts and error history (see toast.go)
+	toasts          []notice
+	errorLog        []notice
+	nextNoticeID    int
+	errorPanelIndex int
+	failingLoads    map[string]bool // Sources of background loads failing since their last success
 
 	// Phase 1 combined findings (investigation_id -> content)
 	phase1Findings map[int]string
@@ -153,13 +194,93 @@ type model struct {
 	cp1Priority       string // Editable copy
 	cp1Loaded         int    // Investigation ID that cp1 fields are loaded for
 
+	// Linear draft form (see linear_draft.go)
+	linearDrafts         map[int]*LinearDraft // nil when there's no linear-draft.md
+	draftFormFor         int // Investigation ID the form was filled for
+	draftTitleInput      textinput.Model
+	draftTeamInput       textinput.Model
+	draftLabelsInput     textinput.Model
+	draftDescArea        textarea.Model
+	draftPriority        int    // index into linearPriorities, -1 if unset
+	draftUnknownPriority string // Priority from the file that isn't in the list
+	draftFocus           int
+	draftProblems        map[int]string // Validation errors by field
+	draftDirty           bool
+	editingDraft         bool
+
+	// Metrics dashboard (see metrics.go)
+	metrics        *metricsReport
+	metricsLoading bool
+
+	// Per-agent controls (see agent_controls.go)
+	agentActionTarget string   // Agent the pending stop confirmation is for
+	agentRetryTargets []string // Agents the retry dialog will re-run
+	agentRetryArea    textarea.Model
+	agentRetryPending int // Retry requests still in flight
+	agentRetryError   string
+
+	// Agent process samples by PID (see procmon.go)
+	procHistory map[int]*procHistory
+
+	// Stalled-agent watchdog (see watchdog.go), by investigation ID
+	watchdog          map[int]*investigationHealth
+	watchdogCheckedAt time.Time
+
+	// Settings screen (see settings_editor.go)
+	settingsEdit settingsEditor
+
+	// KB article tab (see kb.go)
+	kbArticles   map[int]*KBArticle // nil when there's no kb-article.md
+	kbGenerating map[int]bool       // Generation requested, polled until the file appears
+	kbTextarea   textarea.Model
+	kbScroll     int
+	editingKB    bool
+
+	// Editable files as last read or written, and as they were when the
+	// current edit started (see conflict.go)
+	diskStates   map[docKey]diskState
+	editBases    map[docKey]diskState
+	conflict     *saveConflict
+	conflictView int
+
+	// Draft autosave and recovery (see autosave.go). autosaved maps each
+	// draft file written this session to what was last written.
+	autosaved        map[string]string
+	autosavedAt      time.Time
+	autosaveErr      string
+	recovered        []savedDraft // Left by a previous session, not yet restored or discarded
+	recoveryCursor   int
+	recoveryDeferred bool
+
+	// Command palette (see palette.go)
+	paletteQuery  textinput.Model
+	paletteCursor int
+
+	// Response snippet library (see snippets.go)
+	snippets       []Snippet
+	snippetsErr    error
+	snippetQuery   textinput.Model
+	snippetCursor  int
+	newSnippetName textinput.Model
+	newSnippetBody string
+	newSnippetErr  error
+
+	// Edit dataset (see learning.go). generatedRecorded holds the runs whose
+	// generated response was recorded this session.
+	generatedRecorded   map[string]bool
+	editInsights        *editInsights
+	editInsightsLoading bool
+
+	// Version timeline on the Summary tab (see versions.go)
+	versions map[int][]InvestigationVersion
+	history  versionHistory
+
 	// Debug overlay
 	showDebugOverlay bool
 	buildVersion     string
 	buildTime        string
 
 	// Create form
-	showCreateForm     bool
 	createTicketInput  textinput.Model
 	createContextArea  textarea.Model
 	createSkill        int // index into skillOptions
@@ -168,13 +289,11 @@ type model struct {
 	creatingInProgress bool
 
 	// Hard reset form
-	showResetForm       bool
 	resetContextArea    textarea.Model
 	resetError          string
 	resettingInProgress bool
 
 	// Customer reply prompt
-	showReplyPrompt  bool
 	replyContextArea textarea.Model
 	replyError       string
 	approvingReply   bool
@@ -188,6 +307,16 @@ func (m model) getSelectedInvestigation() *Investigation {
 	return &m.investigations[m.selectedIndex]
 }
 
+// findInvestigation looks an investigation up by ID
+func (m model) findInvestigation(id int) *Investigation {
+	for i := range m.investigations {
+		if m.investigations[i].ID == id {
+			return &m.investigations[i]
+		}
+	}
+	return nil
+}
+
 func (m model) getSelectedInvestigationID() int {
 	inv := m.getSelectedInvestigation()
 	if inv == nil {
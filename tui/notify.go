@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Events that can raise a notification
+const (
+	notifyCheckpoint = "checkpoint" // Investigation is waiting at a checkpoint
+	notifyError      = "error"
+	notifyComplete   = "complete"
+	notifyReply      = "reply" // Customer replied (HasNewReply)
+)
+
+var notifyEvents = []string{notifyCheckpoint, notifyError, notifyComplete, notifyReply}
+
+// notifyRule says how one event type is announced
+type notifyRule struct {
+	Bell    bool `json:"bell"`
+	Desktop bool `json:"desktop"` // OSC 9/777 desktop notification
+	Command bool `json:"command"` // Run NotifyConfig.Command
+	// IgnoreQuietHours lets an event through during quiet hours
+	IgnoreQuietHours bool `json:"ignore_quiet_hours"`
+}
+
+// NotifyConfig is notifications.json. Events missing from the file keep
+// their defaults.
+type NotifyConfig struct {
+	// Command runs through sh -c with TRIAGE_EVENT, TRIAGE_TITLE,
+	// TRIAGE_MESSAGE and TRIAGE_INVESTIGATION_ID set, e.g.
+	// notify-send "$TRIAGE_TITLE" "$TRIAGE_MESSAGE"
+	Command string `json:"command"`
+	// Desktop is the escape sequence used: "osc9", "osc777", "both" or
+	// "" to pick one from the terminal.
+	Desktop    string                `json:"desktop_protocol"`
+	QuietHours *quietHours           `json:"quiet_hours"`
+	Events     map[string]notifyRule `json:"events"`
+}
+
+// quietHours is a daily local-time window, "22:00" to "08:00". A window
+// whose end is before its start runs past midnight.
+type quietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// notifyConfig is loaded once at startup (see main)
+var notifyConfig = defaultNotifyConfig()
+
+func defaultNotifyConfig() NotifyConfig {
+	return NotifyConfig{Events: map[string]notifyRule{
+		notifyCheckpoint: {Bell: true, Desktop: true},
+		notifyError:      {Bell: true, Desktop: true},
+		notifyComplete:   {Desktop: true},
+		notifyReply:      {Bell: true, Desktop: true},
+	}}
+}
+
+// notifyConfigPath sits next to settings.json unless overridden
+func notifyConfigPath() string {
+	if path := os.Getenv("TUI_NOTIFY_CONFIG"); path != "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(investigationsDir), "notifications.json")
+}
+
+// loadNotifyConfig reads the config file; a missing file means defaults
+func loadNotifyConfig(path string) (NotifyConfig, error) {
+	cfg := defaultNotifyConfig()
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	var file NotifyConfig
+	if err := json.Unmarshal(content, &file); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	for event, rule := range file.Events {
+		if _, ok := cfg.Events[event]; !ok {
+			return cfg, fmt.Errorf("%s: unknown event %q (want one of %s)", path, event, strings.Join(notifyEvents, ", "))
+		}
+		cfg.Events[event] = rule
+	}
+	switch file.Desktop {
+	case "", "osc9", "osc777", "both":
+	default:
+		return cfg, fmt.Errorf("%s: desktop_protocol must be osc9, osc777 or both", path)
+	}
+	if file.QuietHours != nil {
+		if _, _, err := file.QuietHours.window(); err != nil {
+			return cfg, fmt.Errorf("%s: quiet_hours: %w", path, err)
+		}
+	}
+	cfg.Command, cfg.Desktop, cfg.QuietHours = file.Command, file.Desktop, file.QuietHours
+	return cfg, nil
+}
+
+// window returns the start and end as minutes after midnight
+func (q *quietHours) window() (int, int, error) {
+	parse := func(s string) (int, error) {
+		t, err := time.Parse("15:04", s)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not HH:MM", s)
+		}
+		return t.Hour()*60 + t.Minute(), nil
+	}
+	start, err := parse(q.Start)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parse(q.End)
+	return start, end, err
+}
+
+func (q *quietHours) contains(t time.Time) bool {
+	if q == nil {
+		return false
+	}
+	start, end, err := q.window()
+	if err != nil || start == end {
+		return false
+	}
+	now := t.Hour()*60 + t.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// notifyEvent is one thing worth telling the user about
+type notifyEvent struct {
+	kind            string
+	investigationID int
+	title           string
+	message         string
+}
+
+// detectNotifyEvents compares a refreshed investigation list with the
+// previous one. Investigations that weren't in the previous list are
+// skipped so startup and newly created tickets stay quiet.
+func detectNotifyEvents(prev, next []Investigation) []notifyEvent {
+	before := make(map[int]Investigation, len(prev))
+	for _, inv := range prev {
+		before[inv.ID] = inv
+	}
+	var events []notifyEvent
+	for _, inv := range next {
+		old, ok := before[inv.ID]
+		if !ok {
+			continue
+		}
+		title := strings.TrimSpace(fmt.Sprintf("#%d %s", inv.ID, inv.CustomerName))
+		switch {
+		case inv.Status == "waiting" && (old.Status != "waiting" || old.CurrentCheckpoint != inv.CurrentCheckpoint):
+			events = append(events, notifyEvent{notifyCheckpoint, inv.ID, title,
+				"Waiting at " + checkpointName(inv.CurrentCheckpoint)})
+		case inv.Status == "error" && old.Status != "error":
+			events = append(events, notifyEvent{notifyError, inv.ID, title, "Investigation failed"})
+		case inv.Status == "complete" && old.Status != "complete":
+			events = append(events, notifyEvent{notifyComplete, inv.ID, title, "Investigation complete"})
+		}
+		if inv.HasNewReply == 1 && old.HasNewReply != 1 {
+			message := "Customer replied"
+			if inv.NewReplySummary != "" {
+				message += ": " + inv.NewReplySummary
+			}
+			events = append(events, notifyEvent{notifyReply, inv.ID, title, message})
+		}
+	}
+	return events
+}
+
+// checkpointName is the readable name of a checkpoint key
+func checkpointName(key string) string {
+	for _, cp := range checkpointKeys {
+		if cp.key == key {
+			return cp.name
+		}
+	}
+	if key == "" {
+		return "a checkpoint"
+	}
+	return key
+}
+
+// desktopSequences builds the OSC notification for the configured protocol.
+// iTerm2, WezTerm and Windows Terminal understand OSC 9; most others that
+// support notifications at all (foot, Ghostty, urxvt, kitty) take OSC 777.
+func desktopSequences(protocol, title, message string) []string {
+	if protocol == "" {
+		switch os.Getenv("TERM_PROGRAM") {
+		case "iTerm.app", "WezTerm":
+			protocol = "osc9"
+		default:
+			if os.Getenv("WT_SESSION") != "" {
+				protocol = "osc9"
+			} else {
+				protocol = "osc777"
+			}
+		}
+	}
+	// Control characters would end the sequence early
+	clean := strings.NewReplacer("\x07", "", "\x1b", "", "\n", " ", ";", ",")
+	title, message = clean.Replace(title), clean.Replace(message)
+
+	var seqs []string
+	if protocol == "osc9" || protocol == "both" {
+		seqs = append(seqs, "\x1b]9;"+title+": "+message+"\x07")
+	}
+	if protocol == "osc777" || protocol == "both" {
+		seqs = append(seqs, "\x1b]777;notify;"+title+";"+message+"\x07")
+	}
+	return seqs
+}
+
+// notifyOutput is where the bell and OSC sequences go
+var notifyOutput io.Writer = os.Stdout
+
+// Announce events according to the config. Returns an errMsg when the
+// notification command fails; terminal writes are best effort.
+func sendNotificationsCmd(cfg NotifyConfig, events []notifyEvent) tea.Cmd {
+	return func() tea.Msg {
+		quiet := cfg.QuietHours.contains(time.Now())
+		var failures []string
+		for _, ev := range events {
+			rule := cfg.Events[ev.kind]
+			if quiet && !rule.IgnoreQuietHours {
+				continue
+			}
+			if rule.Bell {
+				io.WriteString(notifyOutput, "\a")
+			}
+			if rule.Desktop {
+				for _, seq := range desktopSequences(cfg.Desktop, ev.title, ev.message) {
+					io.WriteString(notifyOutput, terminalPassthrough(seq))
+				}
+			}
+			if rule.Command && cfg.Command != "" {
+				if err := runNotifyCommand(cfg.Command, ev); err != nil {
+					failures = append(failures, err.Error())
+				}
+			}
+		}
+		if len(failures) > 0 {
+			return errMsg{err: fmt.Errorf("notification command: %s", strings.Join(failures, "; ")), source: "notify"}
+		}
+		return nil
+	}
+}
+
+func runNotifyCommand(command string, ev notifyEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(),
+		"TRIAGE_EVENT="+ev.kind,
+		"TRIAGE_TITLE="+ev.title,
+		"TRIAGE_MESSAGE="+ev.message,
+		fmt.Sprintf("TRIAGE_INVESTIGATION_ID=%d", ev.investigationID),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
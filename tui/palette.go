@@ -0,0 +1,320 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteCommand is one entry in the command palette. Running it replays
+// its shortcut through Update, so the palette and the key share a code path;
+// "Go to" entries move the selection the way ↑↓ do.
+type paletteCommand struct {
+	group    string
+	title    string
+	binding  key.Binding
+	jumpTo   int // Index into m.investigations, or -1
+	disabled bool
+}
+
+// shortcut is the key shown next to the entry, e.g. "ctrl+r"
+func (c paletteCommand) shortcut() string {
+	if c.jumpTo >= 0 || len(c.binding.Keys()) == 0 {
+		return ""
+	}
+	return c.binding.Keys()[0]
+}
+
+// paletteKeyTypes maps binding names to the key messages a terminal sends
+var paletteKeyTypes = map[string]tea.KeyType{
+	"tab":    tea.KeyTab,
+	"enter":  tea.KeyEnter,
+	"esc":    tea.KeyEsc,
+	"up":     tea.KeyUp,
+	"down":   tea.KeyDown,
+	"pgup":   tea.KeyPgUp,
+	"pgdown": tea.KeyPgDown,
+	"ctrl+r": tea.KeyCtrlR,
+	"ctrl+s": tea.KeyCtrlS,
+	"ctrl+o": tea.KeyCtrlO,
+}
+
+// keyMsg is the key press the command's shortcut stands for
+func (c paletteCommand) keyMsg() tea.KeyMsg {
+	k := c.shortcut()
+	if t, ok := paletteKeyTypes[k]; ok {
+		return tea.KeyMsg{Type: t}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+// paletteAvailable reports whether Ctrl+K opens the palette. Editors keep
+// the key for themselves (it deletes to the end of the line).
+func (m model) paletteAvailable() bool {
+	if m.selecting || m.editingDraft || m.editingKB || m.editingResponse {
+		return false
+	}
+	return !m.isShowingCP1Review() || !m.cp1DropdownOpen
+}
+
+// paletteCommands lists what can be done right now, in the order the
+// palette shows them before anything is typed
+func (m model) paletteCommands() []paletteCommand {
+	var cmds []paletteCommand
+	add := func(group, title string, binding key.Binding) {
+		cmds = append(cmds, paletteCommand{group: group, title: title, binding: binding, jumpTo: -1})
+	}
+	inv := m.getSelectedInvestigation()
+
+	switch {
+	case m.history.open && m.activeTab == TabSummary:
+		if m.selectedVersion() != nil && !m.history.restoring {
+			add("History", "Restore selected version", keys.Enter)
+		}
+		add("History", "Refresh versions", keys.Refresh)
+		add("History", "Close version history", keys.Escape)
+		return cmds
+
+	case m.isShowingCP1Review():
+		add("Checkpoint", "Approve classification", keys.Approve)
+		add("Checkpoint", "Change focused field", keys.Enter)
+		add("Checkpoint", "Next field", keys.TabNext)
+		add("Investigations", "Refresh investigations", keys.Refresh)
+		add("View", "Toggle debug overlay", keys.Debug)
+		return append(cmds, m.paletteJumps()...)
+	}
+
+	if inv != nil {
+		if m.hasCheckpoint() {
+			add("Investigation", fmt.Sprintf("Approve %s", checkpointName(inv.CurrentCheckpoint)), keys.Approve)
+		}
+		if inv.Status != "running" {
+			add("Investigation", "Reset and re-investigate", keys.Reset)
+		}
+		if h := m.watchdog[inv.ID]; h.flagged() {
+			add("Investigation", "Retry stalled investigation", keys.Retry)
+		}
+	}
+
+	if inv != nil {
+		switch m.activeTab {
+		case TabSlack, TabLinear, TabPylon, TabCodebase:
+			agent := m.getActiveAgentName()
+			if state := m.getAgentState(inv.ID, agent); state != nil {
+				if state.Status == "running" {
+					add("Agent", "Stop "+agent+" agent", keys.StopAgent)
+				} else {
+					add("Agent", "Retry "+agent+" agent", keys.RetryAgent)
+				}
+			}
+			if len(m.failedAgents(inv.ID)) > 0 {
+				add("Agent", "Retry failed agents", keys.RetryFailed)
+			}
+		case TabSummary:
+			if response := m.getCustomerResponse(inv.ID); response != nil {
+				add("Response", "Edit customer response", keys.Edit)
+				add("Response", "Edit customer response in $EDITOR", keys.EditExternal)
+				add("Response", "Copy customer response", keys.Copy)
+				if !response.PostedToPylon {
+					cmds = append(cmds, paletteCommand{group: "Response", title: "Post response to Pylon",
+						binding: keys.Post, jumpTo: -1, disabled: firstLintError(m.responseLints[inv.ID]) != nil})
+				}
+			}
+			add("Response", "Show version history", keys.History)
+		case TabLinearDraft:
+			if m.draftFormFor == inv.ID {
+				add("Linear draft", "Edit Linear draft", keys.Edit)
+				add("Linear draft", "Edit Linear draft in $EDITOR", keys.EditExternal)
+				add("Linear draft", "Copy Linear draft", keys.Copy)
+				add("Linear draft", "File Linear issue", keys.Post)
+			}
+		case TabKB:
+			if m.kbArticles[inv.ID] != nil {
+				add("KB article", "Edit KB article", keys.Edit)
+				add("KB article", "Edit KB article in $EDITOR", keys.EditExternal)
+				add("KB article", "Copy KB article", keys.Copy)
+				add("KB article", "Export KB article", keys.Export)
+			}
+			add("KB article", "Generate KB article", keys.Generate)
+		}
+		add("View", "Select and copy items", keys.Select)
+
+		tabs := []key.Binding{keys.Tab1, keys.Tab2, keys.Tab3, keys.Tab4, keys.Tab5, keys.Tab6, keys.Tab7}
+		for i, binding := range tabs {
+			if tab := TabType(i); tab != m.activeTab {
+				add("Tabs", "Switch to "+getTabName(tab)+" tab", binding)
+			}
+		}
+		add("Tabs", "Next tab", keys.TabNext)
+	}
+
+	add("Investigations", "New investigation", keys.New)
+	add("Investigations", "Refresh investigations", keys.Refresh)
+	add("View", "Metrics dashboard", keys.Metrics)
+	add("View", "Response edit insights", keys.Insights)
+	add("View", "Open settings", keys.Settings)
+	add("View", "Error history", keys.Errors)
+	add("View", "Toggle debug overlay", keys.Debug)
+	cmds = append(cmds, m.paletteJumps()...)
+	add("App", "Quit", keys.Quit)
+	return cmds
+}
+
+// paletteJumps are "Go to" entries for the other investigations
+func (m model) paletteJumps() []paletteCommand {
+	var cmds []paletteCommand
+	for i, inv := range m.investigations {
+		if i == m.selectedIndex {
+			continue
+		}
+		title := fmt.Sprintf("Go to #%d %s", inv.ID, inv.CustomerName)
+		if inv.Classification != "" {
+			title += " (" + inv.Classification + ")"
+		}
+		cmds = append(cmds, paletteCommand{group: "Go to", title: title, jumpTo: i})
+	}
+	return cmds
+}
+
+// paletteMatches filters the commands by the typed query, best first. With
+// no query the commands keep their listed order.
+func (m model) paletteMatches() []paletteCommand {
+	query := strings.TrimSpace(m.paletteQuery.Value())
+	type scored struct {
+		cmd   paletteCommand
+		score int
+	}
+	var matches []scored
+	for _, c := range m.paletteCommands() {
+		score, ok := fuzzyMatch(query, c.title)
+		if !ok {
+			if score, ok = fuzzyMatch(query, c.group+" "+c.title); !ok {
+				continue
+			}
+			score -= 20 // Matched only with the group's help
+		}
+		matches = append(matches, scored{c, score})
+	}
+	if query != "" {
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	}
+	cmds := make([]paletteCommand, len(matches))
+	for i, s := range matches {
+		cmds[i] = s.cmd
+	}
+	return cmds
+}
+
+// openPalette shows the command palette
+func (m *model) openPalette() tea.Cmd {
+	m.paletteQuery.SetValue("")
+	m.paletteCursor = 0
+	m.pushModal(modalPalette)
+	return m.paletteQuery.Focus()
+}
+
+func (m model) handlePaletteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	matches := m.paletteMatches()
+	switch {
+	case key.Matches(msg, keys.Escape), key.Matches(msg, keys.Palette):
+		m.paletteQuery.Blur()
+		m.closeModal(modalPalette)
+		return m, nil
+
+	case msg.Type == tea.KeyUp, msg.String() == "ctrl+p":
+		if m.paletteCursor > 0 {
+			m.paletteCursor--
+		}
+		return m, nil
+
+	case msg.Type == tea.KeyDown, msg.String() == "ctrl+n":
+		if m.paletteCursor < len(matches)-1 {
+			m.paletteCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Enter):
+		if m.paletteCursor >= len(matches) {
+			return m, nil
+		}
+		c := matches[m.paletteCursor]
+		if c.disabled {
+			return m, nil
+		}
+		m.paletteQuery.Blur()
+		m.closeModal(modalPalette)
+		if c.jumpTo >= 0 {
+			return m, m.moveSelection(c.jumpTo)
+		}
+		return m.Update(c.keyMsg())
+	}
+
+	var cmd tea.Cmd
+	m.paletteQuery, cmd = m.paletteQuery.Update(msg)
+	m.paletteCursor = 0
+	return m, cmd
+}
+
+func (m model) renderPalette() string {
+	dialogWidth := m.modalWidth(80)
+	inner := dialogWidth - 8
+	listHeight := m.modalHeight(28) - 9
+
+	matches := m.paletteMatches()
+	var list string
+	if len(matches) == 0 {
+		list = dimmedTextStyle.Render("No matching commands")
+	} else {
+		start := 0
+		if m.paletteCursor >= listHeight {
+			start = m.paletteCursor - listHeight + 1
+		}
+		keyStyle := lipgloss.NewStyle().Foreground(textMuted)
+		var lines []string
+		for i := start; i < len(matches) && i < start+listHeight; i++ {
+			c := matches[i]
+			shortcut := c.shortcut()
+			title := truncateStr(c.title, inner-lipgloss.Width(shortcut)-18)
+			left := fmt.Sprintf("%-14s %s", truncateStr(c.group, 14), title)
+			if c.disabled {
+				left += " (blocked by style check)"
+			}
+			pad := inner - 2 - lipgloss.Width(left) - lipgloss.Width(shortcut)
+			if pad < 1 {
+				pad = 1
+			}
+			switch {
+			case i == m.paletteCursor:
+				lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(c1Primary).Render("▸ "+left)+
+					strings.Repeat(" ", pad)+keyStyle.Render(shortcut))
+			case c.disabled:
+				lines = append(lines, dimmedTextStyle.Render("  "+left)+strings.Repeat(" ", pad)+keyStyle.Render(shortcut))
+			default:
+				lines = append(lines, "  "+left+strings.Repeat(" ", pad)+keyStyle.Render(shortcut))
+			}
+		}
+		list = strings.Join(lines, "\n")
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Bold(true).Foreground(c1Primary).Render("Command palette"),
+		"",
+		m.paletteQuery.View(),
+		"",
+		lipgloss.NewStyle().Height(listHeight).Render(list),
+		"",
+		dimmedTextStyle.Render(fmt.Sprintf("%d commands • ↑↓: select • Enter: run • Esc: close", len(matches))),
+	)
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(c1Primary).
+		BorderBackground(bgPrimary).
+		Background(bgPrimary).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(content)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// procHistoryLen is how many samples the sparklines show; at the 2s tick
+// that's the last minute.
+const procHistoryLen = 30
+
+// clockTicks is USER_HZ, the unit of utime/stime in /proc/<pid>/stat. It is
+// 100 on every Linux architecture we run on.
+const clockTicks = 100
+
+// errNoProc means this system has no /proc to sample (e.g. macOS). Liveness
+// is still checked with signal 0.
+var errNoProc = errors.New("no /proc on this system")
+
+// procSample is one reading of an agent process
+type procSample struct {
+	At       time.Time
+	Alive    bool
+	CPUTicks uint64  // utime + stime
+	CPU      float64 // Percent of one core since the previous sample
+	RSS      uint64  // Bytes
+	Threads  int
+	Children []int
+	Err      error // Set when the process is alive but couldn't be read
+}
+
+// procHistory keeps recent samples for one PID
+type procHistory struct {
+	samples []procSample
+}
+
+func (h *procHistory) add(s procSample) {
+	if n := len(h.samples); n > 0 && s.Alive {
+		prev := h.samples[n-1]
+		if wall := s.At.Sub(prev.At).Seconds(); wall > 0 && s.CPUTicks >= prev.CPUTicks {
+			s.CPU = float64(s.CPUTicks-prev.CPUTicks) / clockTicks / wall * 100
+		}
+	}
+	h.samples = append(h.samples, s)
+	if len(h.samples) > procHistoryLen {
+		h.samples = h.samples[len(h.samples)-procHistoryLen:]
+	}
+}
+
+func (h *procHistory) latest() (procSample, bool) {
+	if h == nil || len(h.samples) == 0 {
+		return procSample{}, false
+	}
+	return h.samples[len(h.samples)-1], true
+}
+
+// series returns one value per sample taken while the process was alive
+func (h *procHistory) series(value func(procSample) float64) []float64 {
+	var out []float64
+	for _, s := range h.samples {
+		if s.Alive {
+			out = append(out, value(s))
+		}
+	}
+	return out
+}
+
+// pidAlive checks for a process with signal 0. EPERM means it exists but
+// belongs to someone else.
+func pidAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = proc.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// readProcStat parses the fields we need from /proc/<pid>/stat
+func readProcStat(pid int) (ppid int, ticks uint64, threads int, rssPages uint64, err error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	// comm is in parentheses and may contain spaces; fields resume after
+	// the last ")", starting with state (field 3).
+	s := string(data)
+	end := strings.LastIndexByte(s, ')')
+	if end < 0 {
+		return 0, 0, 0, 0, fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+	fields := strings.Fields(s[end+1:])
+	if len(fields) < 22 {
+		return 0, 0, 0, 0, fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+	field := func(n int) uint64 { // n is the 1-based field number from proc(5)
+		v, _ := strconv.ParseUint(fields[n-3], 10, 64)
+		return v
+	}
+	return int(field(4)), field(14) + field(15), int(field(20)), field(24), nil
+}
+
+// childPIDs lists a process's direct children, using the task children file
+// when the kernel provides it and scanning /proc otherwise.
+func childPIDs(pid int) []int {
+	var children []int
+	if data, err := os.ReadFile(fmt.Sprintf("/proc/%d/task/%d/children", pid, pid)); err == nil {
+		for _, f := range strings.Fields(string(data)) {
+			if c, err := strconv.Atoi(f); err == nil {
+				children = append(children, c)
+			}
+		}
+		return children
+	}
+	entries, _ := os.ReadDir("/proc")
+	for _, e := range entries {
+		c, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		if ppid, _, _, _, err := readProcStat(c); err == nil && ppid == pid {
+			children = append(children, c)
+		}
+	}
+	sort.Ints(children)
+	return children
+}
+
+// sampleProc reads one process. A missing process is a sample with Alive
+// false, not an error.
+func sampleProc(pid int, now time.Time) procSample {
+	s := procSample{At: now}
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		s.Alive = pidAlive(pid)
+		if s.Alive {
+			s.Err = errNoProc
+		}
+		return s
+	}
+	_, ticks, threads, rssPages, err := readProcStat(pid)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s
+		}
+		s.Alive = true
+		s.Err = err
+		return s
+	}
+	s.Alive = true
+	s.CPUTicks = ticks
+	s.Threads = threads
+	s.RSS = rssPages * uint64(os.Getpagesize())
+	s.Children = childPIDs(pid)
+	return s
+}
+
+// Sample each PID once
+func sampleAgentProcsCmd(pids []int) tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		samples := make(map[int]procSample, len(pids))
+		for _, pid := range pids {
+			samples[pid] = sampleProc(pid, now)
+		}
+		return procSampledMsg{samples: samples}
+	}
+}
+
+// runningAgentPIDs lists the PIDs of the investigation's running agents
+func (m model) runningAgentPIDs(investigationID int) []int {
+	var pids []int
+	for _, state := range m.agents[investigationID] {
+		if state.Status == "running" && state.PID > 0 {
+			pids = append(pids, state.PID)
+		}
+	}
+	sort.Ints(pids)
+	return pids
+}
+
+// agentProcessGone reports a running agent whose process has exited
+func (m model) agentProcessGone(state *AgentState) bool {
+	if state.Status != "running" || state.PID <= 0 {
+		return false
+	}
+	s, ok := m.procHistory[state.PID].latest()
+	return ok && !s.Alive
+}
+
+func formatBytes(b uint64) string {
+	switch {
+	case b >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(b)/(1<<30))
+	case b >= 1<<20:
+		return fmt.Sprintf("%.0f MB", float64(b)/(1<<20))
+	default:
+		return fmt.Sprintf("%.0f KB", float64(b)/(1<<10))
+	}
+}
+
+// renderAgentResources is the resource line under the agent status header:
+// CPU and RSS with sparklines, threads and child processes. It renders
+// nothing until the agent's process has been sampled.
+func (m model) renderAgentResources(state *AgentState, width int) string {
+	if state.Status != "running" || state.PID <= 0 {
+		return ""
+	}
+	h := m.procHistory[state.PID]
+	s, ok := h.latest()
+	if !ok {
+		return ""
+	}
+	if !s.Alive {
+		warn := lipgloss.NewStyle().Bold(true).Foreground(statusError)
+		return "  " + warn.Render(truncateStr(withIcon(glyphFailed, fmt.Sprintf(
+			"PID %d no longer exists, but the API still reports this agent as running", state.PID)), width-10))
+	}
+	if s.Err != nil {
+		return dimmedTextStyle.Render("  " + truncateStr(fmt.Sprintf("PID %d alive • resource stats unavailable: %v", state.PID, s.Err), width-10))
+	}
+
+	cpu := h.series(func(s procSample) float64 { return s.CPU })
+	rss := h.series(func(s procSample) float64 { return float64(s.RSS) })
+	children := fmt.Sprintf("%d children", len(s.Children))
+	if len(s.Children) > 0 {
+		var ids []string
+		for _, c := range s.Children {
+			ids = append(ids, strconv.Itoa(c))
+		}
+		children += " (" + strings.Join(ids, ", ") + ")"
+	}
+	line := fmt.Sprintf("CPU %5.1f%% %s   RSS %s %s   %d threads   %s",
+		s.CPU, sparkline(cpu), formatBytes(s.RSS), sparkline(rss), s.Threads, children)
+	return "  " + truncateStr(line, width-10)
+}
+
+// pruneProcHistory drops history for PIDs that are no longer watched
+func (m *model) pruneProcHistory(watched []int) {
+	keep := make(map[int]bool, len(watched))
+	for _, pid := range watched {
+		keep[pid] = true
+	}
+	for pid := range m.procHistory {
+		if !keep[pid] {
+			delete(m.procHistory, pid)
+		}
+	}
+}
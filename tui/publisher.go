@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ResponsePublisher delivers a customer response to the ticket. Publish must
+// only return a nil error once the destination has accepted the message.
+type ResponsePublisher interface {
+	Name() string
+	Publish(ctx context.Context, investigationID int, content string) (PublishReceipt, error)
+}
+
+// PublishReceipt records where a response ended up. Receipts are appended to
+// the investigation's pylon-posts.jsonl so the posted state survives restarts.
+type PublishReceipt struct {
+	MessageID string    `json:"message_id"`
+	PostedAt  time.Time `json:"posted_at"`
+	Publisher string    `json:"publisher"`
+	Location  string    `json:"location,omitempty"` // Outbox file for dry runs
+}
+
+// publishTimeout matches the server's limit on the agent that does the post
+const publishTimeout = 5 * time.Minute
+
+// publisher is chosen at startup from -publisher / TUI_PUBLISHER
+var publisher ResponsePublisher = apiPublisher{}
+
+// newPublisher maps a publisher name to an implementation
+func newPublisher(name string) (ResponsePublisher, error) {
+	switch strings.ToLower(name) {
+	case "", "api":
+		return apiPublisher{}, nil
+	case "dry-run", "dryrun", "outbox":
+		return dryRunPublisher{dir: outboxDir()}, nil
+	default:
+		return nil, fmt.Errorf("unknown publisher %q (want api or dry-run)", name)
+	}
+}
+
+// apiPublisher posts through the Express server, which owns the Pylon
+// credentials. The server answers once Pylon has the message.
+type apiPublisher struct{}
+
+func (apiPublisher) Name() string { return "api" }
+
+func (apiPublisher) Publish(ctx context.Context, investigationID int, content string) (PublishReceipt, error) {
+	path := fmt.Sprintf("/api/investigations/%d/responses/post", investigationID)
+	body := map[string]string{"content": content}
+
+	var result struct {
+		MessageID string `json:"messageId"`
+		PostedAt  string `json:"postedAt"`
+	}
+	if err := api.post(ctx, path, body, &result); err != nil {
+		if endpointMissing(err) {
+			return PublishReceipt{}, fmt.Errorf("server has no %s endpoint; update the UI server or run with -publisher=dry-run", path)
+		}
+		return PublishReceipt{}, err
+	}
+	return newReceipt("api", result.MessageID, result.PostedAt)
+}
+
+// newReceipt validates what a real publisher returned. A missing message ID
+// means we can't prove the post happened, so it's reported as a failure.
+func newReceipt(publisherName, messageID, postedAt string) (PublishReceipt, error) {
+	if messageID == "" {
+		return PublishReceipt{}, fmt.Errorf("%s publisher returned no message ID; check the ticket before posting again", publisherName)
+	}
+	at, err := time.Parse(time.RFC3339, postedAt)
+	if err != nil {
+		at = time.Now()
+	}
+	return PublishReceipt{MessageID: messageID, PostedAt: at, Publisher: publisherName}, nil
+}
+
+// dryRunPublisher writes the response to a local outbox instead of posting
+type dryRunPublisher struct {
+	dir string
+}
+
+func (dryRunPublisher) Name() string { return "dry-run" }
+
+func (p dryRunPublisher) Publish(ctx context.Context, investigationID int, content string) (PublishReceipt, error) {
+	if err := os.MkdirAll(p.dir, 0755); err != nil {
+		return PublishReceipt{}, err
+	}
+
+	now := time.Now()
+	id := fmt.Sprintf("dry-run-%d-%s", investigationID, now.Format("20060102T150405"))
+	file := filepath.Join(p.dir, id+".md")
+	body := fmt.Sprintf("<!-- investigation: %d, created: %s -->\n\n%s", investigationID, now.Format(time.RFC3339), content)
+	if err := os.WriteFile(file, []byte(body), 0644); err != nil {
+		return PublishReceipt{}, err
+	}
+	return PublishReceipt{MessageID: id, PostedAt: now, Publisher: "dry-run", Location: file}, nil
+}
+
+// outboxDir sits next to the investigations directory unless overridden
+func outboxDir() string {
+	if dir := os.Getenv("TUI_OUTBOX_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(filepath.Dir(investigationsDir), "outbox")
+}
+
+// Post customer response to Pylon through the configured publisher
+func postToPylonCmd(investigationID int, content string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		defer cancel()
+
+		receipt, err := publisher.Publish(ctx, investigationID, content)
+		if err != nil {
+			return responsePostedMsg{investigationID: investigationID, err: err}
+		}
+		// The post went through; failing to record it is only a warning
+		recordErr := appendPublishReceipt(investigationID, receipt)
+		return responsePostedMsg{investigationID: investigationID, content: content, receipt: receipt, recordErr: recordErr}
+	}
+}
+
+func (msg responsePostedMsg) outwardErr() error  { return msg.err }
+func (msg responsePostedMsg) outwardRef() string { return msg.receipt.MessageID }
+
+// publishLogPath is in the investigation's root directory, like
+// customer-response.md. Empty when the investigation has no directory.
+func publishLogPath(investigationID int) string {
+	dir := fmt.Sprintf("%s/%d", investigationsDir, investigationID)
+	if _, err := os.Stat(dir); err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pylon-posts.jsonl")
+}
+
+func appendPublishReceipt(investigationID int, receipt PublishReceipt) error {
+	path := publishLogPath(investigationID)
+	if path == "" {
+		return fmt.Errorf("investigation %d has no directory to record the post in", investigationID)
+	}
+	line, err := json.Marshal(receipt)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// lastPublishReceipt returns the most recent recorded post, if any
+func lastPublishReceipt(investigationID int) *PublishReceipt {
+	path := publishLogPath(investigationID)
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var last *PublishReceipt
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r PublishReceipt
+		if json.Unmarshal(scanner.Bytes(), &r) == nil && r.MessageID != "" {
+			last = &r
+		}
+	}
+	return last
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// outwardKind is a destination outside this machine. Every action that
+// publishes to one goes through requestOutward so settings.safety applies.
+type outwardKind string
+
+const (
+	outwardPylon  outwardKind = "pylon"
+	outwardLinear outwardKind = "linear"
+	outwardSlack  outwardKind = "slack"
+	outwardNotion outwardKind = "notion"
+)
+
+// Approval methods for settings.safety.approval_method
+const (
+	approvalConfirm        = "confirm" // Only used when approval isn't required
+	approvalTypeTicket     = "type_ticket_number"
+	approvalSecondReviewer = "second_reviewer"
+)
+
+// outwardRequest is an outward action waiting for, or running after, approval
+type outwardRequest struct {
+	kind            outwardKind
+	investigationID int
+	title           string // e.g. "Post response to Pylon ticket #8314"
+	preview         string // What will be sent, shown in the dialog
+	busyLabel       string // Shown while run is in flight
+	detail          string // Extra audit context, e.g. the publisher
+	run             tea.Cmd
+}
+
+// outwardResult is implemented by the messages outward commands return so the
+// audit log can record how the attempt ended.
+type outwardResult interface {
+	outwardErr() error
+	outwardRef() string // Remote ID of what was created, if any
+}
+
+// approvalPolicy returns whether kind needs explicit human approval and how
+// it must be given. Without settings every action requires approval.
+func (m model) approvalPolicy(kind outwardKind) (bool, string) {
+	method := approvalTypeTicket
+	if m.settings == nil {
+		return true, method
+	}
+	safety := m.settings.Safety
+	if safety.ApprovalMethod == approvalSecondReviewer {
+		method = approvalSecondReviewer
+	}
+
+	var flag *bool
+	switch kind {
+	case outwardPylon:
+		flag = safety.RequireHumanApprovalForPylon
+	case outwardLinear:
+		flag = safety.RequireHumanApprovalForLinear
+	case outwardSlack:
+		flag = safety.RequireHumanApprovalForSlack
+	case outwardNotion:
+		flag = safety.RequireHumanApprovalForNotion
+	}
+	if flag != nil && !*flag {
+		return false, approvalConfirm
+	}
+	return true, method
+}
+
+// requestOutward opens the approval dialog for req and logs the attempt
+func (m *model) requestOutward(req outwardRequest) tea.Cmd {
+	_, method := m.approvalPolicy(req.kind)
+	m.outward = &req
+	m.outwardMethod = method
+	m.outwardBusy = false
+	m.approvalError = ""
+	m.approvalTicketInput.Reset()
+	m.approvalReviewerInput.Reset()
+	m.approvalFocus = 0
+	m.approvalTicketInput.Focus()
+	m.approvalReviewerInput.Blur()
+	m.pushModal(modalApproval)
+	return auditCmd(auditEntryFor(req, method, "requested", "", req.detail))
+}
+
+// handleApprovalKey handles the approval dialog
+func (m model) handleApprovalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.outward == nil {
+		m.closeModal(modalApproval)
+		return m, nil
+	}
+	if m.outwardBusy {
+		return m, nil // Wait for the result; the dialog closes when it arrives
+	}
+	req := *m.outward
+	typed := m.outwardMethod != approvalConfirm
+
+	switch {
+	case key.Matches(msg, keys.Escape), !typed && key.Matches(msg, keys.No):
+		m.closeModal(modalApproval)
+		m.outward = nil
+		return m, auditCmd(auditEntryFor(req, m.outwardMethod, "cancelled", "", req.detail))
+
+	case msg.String() == "tab" || msg.String() == "shift+tab":
+		if m.outwardMethod == approvalSecondReviewer {
+			m.approvalFocus = 1 - m.approvalFocus
+			if m.approvalFocus == 0 {
+				m.approvalTicketInput.Focus()
+				m.approvalReviewerInput.Blur()
+			} else {
+				m.approvalTicketInput.Blur()
+				m.approvalReviewerInput.Focus()
+			}
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Enter), !typed && key.Matches(msg, keys.Yes):
+		reviewer, err := m.validateApproval(req)
+		if err != nil {
+			m.approvalError = err.Error()
+			return m, auditCmd(auditEntryFor(req, m.outwardMethod, "rejected", reviewer, err.Error()))
+		}
+		m.approvalError = ""
+		m.outwardBusy = true
+		return m, auditedRunCmd(req, m.outwardMethod, reviewer)
+	}
+
+	if !typed {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	if m.approvalFocus == 0 {
+		m.approvalTicketInput, cmd = m.approvalTicketInput.Update(msg)
+	} else {
+		m.approvalReviewerInput, cmd = m.approvalReviewerInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// validateApproval checks what was typed against the configured method and
+// returns the second reviewer's name when one is required. Nothing here can
+// prove who typed the name, so it is recorded as an attestation, not a check.
+func (m model) validateApproval(req outwardRequest) (string, error) {
+	if m.outwardMethod == approvalConfirm {
+		return "", nil
+	}
+
+	if strings.TrimSpace(m.approvalTicketInput.Value()) != strconv.Itoa(req.investigationID) {
+		return "", fmt.Errorf("type the ticket number (%d) to confirm", req.investigationID)
+	}
+
+	if m.outwardMethod != approvalSecondReviewer {
+		return "", nil
+	}
+	reviewer := strings.TrimSpace(m.approvalReviewerInput.Value())
+	if reviewer == "" {
+		return "", fmt.Errorf("a second reviewer must approve this action")
+	}
+	if strings.EqualFold(reviewer, currentUser()) {
+		return reviewer, fmt.Errorf("the reviewer must be someone other than %s", currentUser())
+	}
+	return reviewer, nil
+}
+
+// finishOutward closes the approval dialog once the action's result arrives
+func (m *model) finishOutward() {
+	m.outwardBusy = false
+	m.outward = nil
+	m.closeModal(modalApproval)
+}
+
+func (m model) renderApprovalDialog() string {
+	dialogWidth := m.modalWidth(70)
+	dialogHeight := m.modalHeight(18)
+	if m.outward == nil {
+		return ""
+	}
+	req := m.outward
+
+	required, _ := m.approvalPolicy(req.kind)
+	color := c1Primary
+	if required {
+		color = statusRunning
+	}
+
+	header := lipgloss.NewStyle().Bold(true).Foreground(color).
+		Render(withIcon(glyphPost, req.title))
+
+	var policy string
+	switch m.outwardMethod {
+	case approvalTypeTicket:
+		policy = fmt.Sprintf("settings.safety requires approval for %s: type the ticket number.", req.kind)
+	case approvalSecondReviewer:
+		policy = fmt.Sprintf("settings.safety requires a second reviewer for %s. "+
+			"The name isn't verified: it is recorded in the audit log as the reviewer's attestation.", req.kind)
+	default:
+		policy = "Approval is not required by settings.safety."
+	}
+	policyLine := dimmedTextStyle.Width(dialogWidth - 6).Render(policy)
+
+	preview := lipgloss.NewStyle().
+		Foreground(textSecondary).
+		Width(dialogWidth - 6).
+		MaxHeight(6).
+		Render(req.preview)
+
+	var fields []string
+	if m.outwardMethod != approvalConfirm {
+		m.approvalTicketInput.Width = 12
+		fields = append(fields, "Ticket number: "+m.approvalTicketInput.View())
+	}
+	if m.outwardMethod == approvalSecondReviewer {
+		m.approvalReviewerInput.Width = 24
+		fields = append(fields, "Attested by:   "+m.approvalReviewerInput.View())
+	}
+
+	var errorLine string
+	if m.approvalError != "" {
+		errorLine = lipgloss.NewStyle().Foreground(statusError).Bold(true).Render(m.approvalError)
+	}
+
+	var footer string
+	switch {
+	case m.outwardBusy:
+		footer = m.spinner.View() + " " + req.busyLabel
+	case m.outwardMethod == approvalConfirm:
+		footer = dimmedTextStyle.Render("[Y] Yes    [N] No")
+	case m.outwardMethod == approvalSecondReviewer:
+		footer = dimmedTextStyle.Render("Tab: switch field • Enter: approve • Esc: cancel")
+	default:
+		footer = dimmedTextStyle.Render("Enter: approve • Esc: cancel")
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		policyLine,
+		"",
+		preview,
+		"",
+		strings.Join(fields, "\n"),
+		errorLine,
+		"",
+		footer,
+	)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(color).
+		BorderBackground(bgPrimary).
+		Background(bgPrimary).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Height(dialogHeight).
+		Render(content)
+}
+
+func newApprovalInputs() (textinput.Model, textinput.Model) {
+	ticket := textinput.New()
+	ticket.Placeholder = "e.g. 8314"
+	ticket.CharLimit = 10
+
+	reviewer := textinput.New()
+	reviewer.Placeholder = "reviewer name"
+	reviewer.CharLimit = 64
+	return ticket, reviewer
+}
+
+// auditEntry is one line of the outward action audit log
+type auditEntry struct {
+	Time            time.Time   `json:"ts"`
+	Action          outwardKind `json:"action"`
+	InvestigationID int         `json:"investigation_id"`
+	Title           string      `json:"title"`
+	Outcome         string      `json:"outcome"` // requested, cancelled, rejected, approved, succeeded, failed
+	Method          string      `json:"method"`
+	User            string      `json:"user"`
+	Reviewer        string      `json:"reviewer,omitempty"`
+	ReviewerCheck   string      `json:"reviewer_check,omitempty"` // How Reviewer was established; always self-attested for now
+	Detail          string      `json:"detail,omitempty"`
+}
+
+// auditLogPath sits next to settings.json unless overridden
+func auditLogPath() string {
+	if path := os.Getenv("TUI_AUDIT_LOG"); path != "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(investigationsDir), "audit-log.jsonl")
+}
+
+func auditEntryFor(req outwardRequest, method, outcome, reviewer, detail string) auditEntry {
+	return auditEntry{
+		Time:            time.Now(),
+		Action:          req.kind,
+		InvestigationID: req.investigationID,
+		Title:           req.title,
+		Outcome:         outcome,
+		Method:          method,
+		User:            currentUser(),
+		Reviewer:        reviewer,
+		ReviewerCheck:   reviewerCheck(reviewer),
+		Detail:          detail,
+	}
+}
+
+// reviewerCheck says how the reviewer was established. The name is typed on
+// the operator's own terminal, so the log must not read it as verified.
+func reviewerCheck(reviewer string) string {
+	if reviewer == "" {
+		return ""
+	}
+	return "self-attested"
+}
+
+func appendAudit(entry auditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(auditLogPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// auditCmd records an entry that doesn't gate anything (requests, cancels)
+func auditCmd(entry auditEntry) tea.Cmd {
+	return func() tea.Msg {
+		if err := appendAudit(entry); err != nil {
+			return errMsg{err: fmt.Errorf("failed to write audit log: %w", err), source: "audit log"}
+		}
+		return nil
+	}
+}
+
+// auditedRunCmd runs an approved action. The approval must reach the audit
+// log before anything is sent; if it can't be written the action is refused.
+func auditedRunCmd(req outwardRequest, method, reviewer string) tea.Cmd {
+	return func() tea.Msg {
+		if err := appendAudit(auditEntryFor(req, method, "approved", reviewer, req.detail)); err != nil {
+			return outwardRefusedMsg{err: fmt.Errorf("not sent: audit log unavailable: %w", err)}
+		}
+
+		msg := req.run()
+		entry := auditEntryFor(req, method, "succeeded", reviewer, req.detail)
+		if result, ok := msg.(outwardResult); ok {
+			if err := result.outwardErr(); err != nil {
+				entry.Outcome = "failed"
+				entry.Detail = strings.TrimSpace(req.detail + " " + err.Error())
+			} else if ref := result.outwardRef(); ref != "" {
+				entry.Detail = strings.TrimSpace(req.detail + " ref=" + ref)
+			}
+		}
+		if err := appendAudit(entry); err != nil {
+			auditErr := errMsg{err: fmt.Errorf("failed to write audit log: %w", err), source: "audit log"}
+			return tea.BatchMsg{
+				func() tea.Msg { return msg },
+				func() tea.Msg { return auditErr },
+			}
+		}
+		return msg
+	}
+}
+
+// currentUser names the operator for the audit log
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,451 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// copyItem is one selectable block in selection mode: a finding, a summary
+// section or a log line.
+type copyItem struct {
+	label    string // One-line description shown in the list
+	plain    string
+	markdown string
+}
+
+// selectionPane is a list of items the cursor can move through. Log panes
+// are copied as a code block so ranges keep their line structure.
+type selectionPane struct {
+	name  string
+	slug  string // Used in permalinks
+	items []copyItem
+	code  bool
+}
+
+// selectionPanes builds the copyable content of the active tab
+func (m model) selectionPanes() []selectionPane {
+	inv := m.getSelectedInvestigation()
+	if inv == nil {
+		return nil
+	}
+
+	switch m.activeTab {
+	case TabSlack, TabLinear, TabPylon, TabCodebase:
+		agentName := m.getActiveAgentName()
+		state := m.getAgentState(inv.ID, agentName)
+		if state == nil {
+			if p1 := m.phase1Findings[inv.ID]; p1 != "" {
+				return []selectionPane{findingsPane("Phase 1 findings", "phase1", parseMarkdownFindings(p1))}
+			}
+			return nil
+		}
+		slug := strings.ToLower(agentName)
+		return []selectionPane{
+			findingsPane(agentName+" findings", slug+"/findings", state.Findings),
+			logsPane(agentName+" log", slug+"/log", state.Logs),
+		}
+
+	case TabSummary:
+		var panes []selectionPane
+		if summary := m.getSummary(inv.ID); summary != nil {
+			panes = append(panes, summaryPane(summary))
+		}
+		if response := m.getCustomerResponse(inv.ID); response != nil && response.Content != "" {
+			panes = append(panes, selectionPane{
+				name: "Customer response",
+				slug: "response",
+				items: []copyItem{{
+					label:    truncateStr(firstLine(response.Content), 60),
+					plain:    response.Content,
+					markdown: response.Content,
+				}},
+			})
+			paragraphs := selectionPane{name: "Response paragraphs", slug: "response/paragraphs"}
+			for _, p := range strings.Split(response.Content, "\n\n") {
+				if p = strings.TrimSpace(p); p != "" {
+					paragraphs.items = append(paragraphs.items, copyItem{label: truncateStr(firstLine(p), 60), plain: p, markdown: p})
+				}
+			}
+			if len(paragraphs.items) > 1 {
+				panes = append(panes, paragraphs)
+			}
+		}
+		return panes
+
+	case TabLinearDraft:
+		if m.draftFormFor != inv.ID || m.linearDrafts[inv.ID] == nil {
+			return nil
+		}
+		d := m.formDraft()
+		fields := fmt.Sprintf("Team: %s\nPriority: %s\nLabels: %s", d.Team, d.Priority, strings.Join(d.Labels, ", "))
+		return []selectionPane{{
+			name: "Linear draft",
+			slug: "linear-draft",
+			items: []copyItem{
+				{label: "Title: " + truncateStr(d.Title, 60), plain: d.Title, markdown: d.Title},
+				{label: "Team, priority and labels", plain: fields, markdown: fields},
+				{label: "Description: " + truncateStr(firstLine(d.Description), 50), plain: d.Description, markdown: d.Description},
+			},
+		}}
+
+	case TabKB:
+		article := m.kbArticles[inv.ID]
+		if article == nil {
+			return nil
+		}
+		pane := selectionPane{name: "KB article", slug: "kb-article"}
+		for _, section := range article.Sections() {
+			pane.items = append(pane.items, copyItem{
+				label:    section.Heading,
+				plain:    section.Heading + "\n" + section.Body,
+				markdown: "## " + section.Heading + "\n" + section.Body,
+			})
+		}
+		return []selectionPane{pane}
+	}
+	return nil
+}
+
+func findingsPane(name, slug string, findings []Finding) selectionPane {
+	pane := selectionPane{name: name, slug: slug}
+	for i, f := range findings {
+		plain := []string{f.Title}
+		md := []string{"**" + f.Title + "**"}
+		for _, d := range f.Details {
+			plain = append(plain, "  - "+d)
+			md = append(md, "- "+d)
+		}
+		pane.items = append(pane.items, copyItem{
+			label:    fmt.Sprintf("Finding #%d: %s", i+1, f.Title),
+			plain:    strings.Join(plain, "\n"),
+			markdown: strings.Join(md, "\n"),
+		})
+	}
+	return pane
+}
+
+func logsPane(name, slug string, logs []LogEntry) selectionPane {
+	pane := selectionPane{name: name, slug: slug, code: true}
+	for _, l := range logs {
+		line := fmt.Sprintf("%s [%s] %s", l.Timestamp.Format("15:04:05"), strings.ToUpper(l.Level), l.Message)
+		pane.items = append(pane.items, copyItem{label: line, plain: line, markdown: line})
+	}
+	return pane
+}
+
+func summaryPane(summary *InvestigationSummary) selectionPane {
+	pane := selectionPane{name: "Summary", slug: "summary"}
+	add := func(title string, lines []string, numbered bool) {
+		if len(lines) == 0 {
+			return
+		}
+		plain := []string{title}
+		md := []string{"### " + title}
+		for i, l := range lines {
+			bullet := "- "
+			if numbered {
+				bullet = fmt.Sprintf("%d. ", i+1)
+			}
+			plain = append(plain, "  "+bullet+l)
+			md = append(md, bullet+l)
+		}
+		pane.items = append(pane.items, copyItem{
+			label:    fmt.Sprintf("%s (%d)", title, len(lines)),
+			plain:    strings.Join(plain, "\n"),
+			markdown: strings.Join(md, "\n"),
+		})
+	}
+
+	if summary.RootCause != "" {
+		pane.items = append(pane.items, copyItem{
+			label:    "Root cause: " + truncateStr(firstLine(summary.RootCause), 50),
+			plain:    "Root cause\n" + summary.RootCause,
+			markdown: "### Root cause\n" + summary.RootCause,
+		})
+	}
+	for _, agentName := range []string{"Slack", "Linear", "Pylon", "Codebase"} {
+		add("Key findings — "+agentName, summary.KeyFindings[agentName], false)
+	}
+	add("Open questions", summary.OpenQuestions, false)
+	add("Next steps", summary.NextSteps, true)
+	return pane
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// enterSelection starts selection mode on the active tab
+func (m *model) enterSelection() tea.Cmd {
+	panes := m.selectionPanes()
+	if len(panes) == 0 {
+		return m.notify(severityInfo, "select", "Nothing to select on this tab yet", nil)
+	}
+	m.selecting = true
+	m.selPane = 0
+	m.selCursor = 0
+	m.selAnchor = -1
+	return nil
+}
+
+// selectionRange returns the selected [from, to] item indexes
+func (m model) selectionRange() (int, int) {
+	if m.selAnchor < 0 {
+		return m.selCursor, m.selCursor
+	}
+	if m.selAnchor < m.selCursor {
+		return m.selAnchor, m.selCursor
+	}
+	return m.selCursor, m.selAnchor
+}
+
+// clampSelection keeps the cursor and range anchor inside a pane of n items;
+// panes grow and shrink as polls land. With no items the cursor rests at 0
+// and there is no range.
+func (m *model) clampSelection(n int) {
+	if m.selCursor > n-1 {
+		m.selCursor = n - 1
+	}
+	if m.selCursor < 0 {
+		m.selCursor = 0
+	}
+	if n == 0 {
+		m.selAnchor = -1
+	} else if m.selAnchor > n-1 {
+		m.selAnchor = n - 1
+	}
+}
+
+// handleSelectionKey handles keys while selection mode is active
+func (m model) handleSelectionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	panes := m.selectionPanes()
+	if len(panes) == 0 {
+		m.selecting = false
+		return m, nil
+	}
+	if m.selPane >= len(panes) {
+		m.selPane = 0
+	}
+	pane := panes[m.selPane]
+	m.clampSelection(len(pane.items))
+
+	switch {
+	case key.Matches(msg, keys.Escape), key.Matches(msg, keys.Select):
+		m.selecting = false
+		return m, nil
+
+	case key.Matches(msg, keys.Up):
+		if m.selCursor > 0 {
+			m.selCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Down):
+		if m.selCursor < len(pane.items)-1 {
+			m.selCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.TabNext):
+		m.selPane = (m.selPane + 1) % len(panes)
+		m.selCursor = 0
+		m.selAnchor = -1
+		return m, nil
+
+	case msg.String() == " ":
+		// Start or clear a range at the cursor
+		if m.selAnchor >= 0 {
+			m.selAnchor = -1
+		} else {
+			m.selAnchor = m.selCursor
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Enter), key.Matches(msg, keys.Copy):
+		return m.copySelection(pane, "plain text")
+
+	case msg.String() == "m":
+		return m.copySelection(pane, "markdown")
+
+	case msg.String() == "L":
+		return m.copySelection(pane, "permalink")
+
+	case msg.String() == "n":
+		// Save the selection to the snippet library
+		if len(pane.items) == 0 {
+			return m, nil
+		}
+		from, to := m.selectionRange()
+		m.selecting = false
+		return m, m.startNewSnippet(selectionPlain(pane, pane.items[from:to+1]))
+	}
+	return m, nil
+}
+
+// copySelection copies the selected items in the given format and leaves
+// selection mode.
+func (m model) copySelection(pane selectionPane, format string) (tea.Model, tea.Cmd) {
+	inv := m.getSelectedInvestigation()
+	if inv == nil || len(pane.items) == 0 {
+		return m, nil
+	}
+	from, to := m.selectionRange()
+	items := pane.items[from : to+1]
+
+	what := fmt.Sprintf("%s item %d", pane.name, from+1)
+	if to > from {
+		what = fmt.Sprintf("%s items %d-%d", pane.name, from+1, to+1)
+	}
+
+	var text string
+	switch format {
+	case "markdown":
+		text = selectionMarkdown(pane, items)
+	case "permalink":
+		text = selectionPermalink(inv, pane, items, from, to)
+	default:
+		text = selectionPlain(pane, items)
+	}
+
+	m.selecting = false
+	return m, copyTextCmd(what+" as "+format, text)
+}
+
+func selectionPlain(pane selectionPane, items []copyItem) string {
+	sep := "\n\n"
+	if pane.code {
+		sep = "\n"
+	}
+	parts := make([]string, len(items))
+	for i, it := range items {
+		parts[i] = it.plain
+	}
+	return strings.Join(parts, sep)
+}
+
+func selectionMarkdown(pane selectionPane, items []copyItem) string {
+	if pane.code {
+		return "```\n" + selectionPlain(pane, items) + "\n```"
+	}
+	parts := make([]string, len(items))
+	for i, it := range items {
+		parts[i] = it.markdown
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// selectionPermalink is a markdown link that names the investigation, run
+// and item so it can be pasted into Slack or Linear and traced back later.
+func selectionPermalink(inv *Investigation, pane selectionPane, items []copyItem, from, to int) string {
+	run := inv.CurrentRunNumber
+	if run == 0 {
+		run = 1
+	}
+	anchor := fmt.Sprintf("%d", from+1)
+	if to > from {
+		anchor = fmt.Sprintf("%d-%d", from+1, to+1)
+	}
+	url := fmt.Sprintf("triage://investigations/%d/runs/%d/%s#%s", inv.ID, run, pane.slug, anchor)
+
+	label := items[0].label
+	if len(items) > 1 {
+		label = fmt.Sprintf("%s (%d items)", pane.name, len(items))
+	}
+	return fmt.Sprintf("[#%d run %d — %s](%s)", inv.ID, run, truncateStr(label, 80), url)
+}
+
+// renderSelectionView replaces the tab content while selection mode is on:
+// the pane's items with the cursor and range highlighted, and a preview of
+// what will be copied.
+func (m model) renderSelectionView(width, height int) string {
+	panes := m.selectionPanes()
+	if len(panes) == 0 {
+		return contentPanelStyle.Width(width - 4).Height(height - 2).Render(emptyStateStyle.Render("Nothing to select"))
+	}
+	paneIdx := m.selPane
+	if paneIdx >= len(panes) {
+		paneIdx = 0
+	}
+	pane := panes[paneIdx]
+	m.clampSelection(len(pane.items))
+
+	var tabs []string
+	for i, p := range panes {
+		label := fmt.Sprintf(" %s (%d) ", p.name, len(p.items))
+		if i == paneIdx {
+			tabs = append(tabs, activeTabStyle.Render(label))
+		} else {
+			tabs = append(tabs, inactiveTabStyle.Render(label))
+		}
+	}
+	header := lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+
+	// Half the height for the list, the rest for the preview
+	innerHeight := height - 8
+	listHeight := innerHeight / 2
+	if listHeight < 3 {
+		listHeight = 3
+	}
+	previewHeight := innerHeight - listHeight - 1
+
+	from, to := m.selectionRange()
+	start := 0
+	if m.selCursor >= listHeight {
+		start = m.selCursor - listHeight + 1
+	}
+	var rows []string
+	if len(pane.items) == 0 {
+		rows = append(rows, emptyStateStyle.Render("Nothing in this pane"))
+	}
+	for i := start; i < len(pane.items) && i < start+listHeight; i++ {
+		marker := "  "
+		if i >= from && i <= to {
+			marker = "▸ "
+		}
+		line := truncateStr(marker+pane.items[i].label, width-10)
+		switch {
+		case i == m.selCursor:
+			line = selectedItemStyle.UnsetPadding().Render(line)
+		case i >= from && i <= to:
+			line = lipgloss.NewStyle().Foreground(c1Primary).Render(line)
+		}
+		rows = append(rows, line)
+	}
+	list := lipgloss.NewStyle().Height(listHeight).Render(strings.Join(rows, "\n"))
+
+	var preview string
+	if len(pane.items) > 0 {
+		preview = selectionMarkdown(pane, pane.items[from:to+1])
+	}
+	previewBox := lipgloss.NewStyle().
+		Foreground(textSecondary).
+		Width(width - 8).
+		Height(previewHeight).
+		MaxHeight(previewHeight).
+		Render(preview)
+
+	rangeHint := "Space: start range"
+	if m.selAnchor >= 0 {
+		rangeHint = "Space: clear range"
+	}
+	footer := dimmedTextStyle.Render("↑↓: move • " + rangeHint + " • Tab: pane • c/Enter: copy text • m: markdown • L: permalink • n: save as snippet • Esc: done")
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		sectionHeaderStyle.Render("SELECT TO COPY"),
+		header,
+		list,
+		dimmedTextStyle.Render(strings.Repeat("─", width-8)),
+		previewBox,
+		footer,
+	)
+	return contentPanelStyle.Width(width - 4).Height(height - 2).Render(content)
+}
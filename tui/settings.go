@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// settingsPath is the settings.json the Express server reads and writes
+var settingsPath = filepath.Join(filepath.Dir(investigationsDir), "settings.json")
+
+// Settings mirrors the parts of settings.json the TUI acts on
+type Settings struct {
+	Safety        SafetySettings        `json:"safety"`
+	Timeouts      TimeoutSettings       `json:"timeouts"`
+	ResponseStyle ResponseStyleSettings `json:"customer_response_style"`
+}
+
+// ResponseStyleSettings are the house rules customer responses are linted
+// against. never_do entries are phrases, or /regex/ for patterns.
+type ResponseStyleSettings struct {
+	Tone              string   `json:"tone"`
+	AlwaysInclude     []string `json:"always_include"`
+	NeverDo           []string `json:"never_do"`
+	LearnFromEdits    bool     `json:"learn_from_edits"`
+	MaxParagraphWords int      `json:"max_paragraph_words"`
+}
+
+// defaultMaxParagraphWords is where a paragraph starts reading as a wall of text
+const defaultMaxParagraphWords = 120
+
+// responseStyle is the style rules, empty when settings haven't loaded
+func (s *Settings) responseStyle() ResponseStyleSettings {
+	if s == nil {
+		return ResponseStyleSettings{}
+	}
+	return s.ResponseStyle
+}
+
+// TimeoutSettings bound how long agents may run; zero means use the default
+type TimeoutSettings struct {
+	InvestigationMaxDurationSeconds int `json:"investigation_max_duration_seconds"`
+	AgentIdleTimeoutSeconds         int `json:"agent_idle_timeout_seconds"`
+}
+
+// Defaults match the shipped settings.json
+const (
+	defaultInvestigationMaxDuration = 900 * time.Second
+	defaultAgentIdleTimeout         = 300 * time.Second
+)
+
+// agentIdleTimeout is how long a running agent may go without logging
+func (s *Settings) agentIdleTimeout() time.Duration {
+	if s == nil || s.Timeouts.AgentIdleTimeoutSeconds <= 0 {
+		return defaultAgentIdleTimeout
+	}
+	return time.Duration(s.Timeouts.AgentIdleTimeoutSeconds) * time.Second
+}
+
+// investigationMaxDuration is how long an agent may run in total
+func (s *Settings) investigationMaxDuration() time.Duration {
+	if s == nil || s.Timeouts.InvestigationMaxDurationSeconds <= 0 {
+		return defaultInvestigationMaxDuration
+	}
+	return time.Duration(s.Timeouts.InvestigationMaxDurationSeconds) * time.Second
+}
+
+// SafetySettings gates outward-facing actions. Approval flags are pointers so
+// a missing key can be told apart from an explicit false; missing means
+// approval is required.
+type SafetySettings struct {
+	C1Readonly                    bool  `json:"c1_readonly"`
+	RequireHumanApprovalForLinear *bool `json:"require_human_approval_for_linear"`
+	RequireHumanApprovalForPylon  *bool `json:"require_human_approval_for_pylon"`
+	RequireHumanApprovalForNotion *bool `json:"require_human_approval_for_notion"`
+	RequireHumanApprovalForSlack  *bool `json:"require_human_approval_for_slack"`
+	// ApprovalMethod is "type_ticket_number" (default) or "second_reviewer"
+	ApprovalMethod string `json:"approval_method"`
+}
+
+// Load settings from the Express API, falling back to settings.json on disk
+// when the server is down.
+func loadSettingsCmd() tea.Cmd {
+	return func() tea.Msg {
+		var s Settings
+		apiErr := api.get(context.Background(), "/api/settings", &s)
+		if apiErr == nil {
+			return settingsLoadedMsg{settings: &s}
+		}
+
+		content, err := os.ReadFile(settingsPath)
+		if err != nil {
+			return settingsLoadedMsg{err: fmt.Errorf("API: %v; file: %w", apiErr, err)}
+		}
+		s = Settings{}
+		if err := json.Unmarshal(content, &s); err != nil {
+			return settingsLoadedMsg{err: fmt.Errorf("failed to parse settings.json: %w", err)}
+		}
+		return settingsLoadedMsg{settings: &s}
+	}
+}
@@ -0,0 +1,783 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// jsonObject is a JSON object that keeps its key order, so saving settings
+// doesn't reshuffle settings.json. Values are jsonObject, []interface{},
+// json.Number, string, bool or nil.
+type jsonObject []jsonMember
+
+type jsonMember struct {
+	Key   string
+	Value interface{}
+}
+
+func (o *jsonObject) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeOrdered(dec)
+	if err != nil {
+		return err
+	}
+	obj, ok := v.(jsonObject)
+	if !ok {
+		return fmt.Errorf("expected a JSON object")
+	}
+	*o = obj
+	return nil
+}
+
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := jsonObject{}
+			for dec.More() {
+				k, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj = append(obj, jsonMember{Key: k.(string), Value: v})
+			}
+			_, err := dec.Token() // }
+			return obj, err
+		case '[':
+			arr := []interface{}{}
+			for dec.More() {
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+			_, err := dec.Token() // ]
+			return arr, err
+		}
+	}
+	return tok, nil
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(m.Key)
+		b.Write(k)
+		b.WriteByte(':')
+		v, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// lookup walks a dotted path of object keys
+func (o jsonObject) lookup(path []string) (interface{}, bool) {
+	var cur interface{} = o
+	for _, k := range path {
+		obj, ok := cur.(jsonObject)
+		if !ok {
+			return nil, false
+		}
+		found := false
+		for _, m := range obj {
+			if m.Key == k {
+				cur, found = m.Value, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// with returns a copy of o with the value at path replaced, creating
+// missing objects on the way. o itself is not modified.
+func (o jsonObject) with(path []string, value interface{}) jsonObject {
+	out := append(jsonObject(nil), o...)
+	for i, m := range out {
+		if m.Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			out[i].Value = value
+		} else {
+			child, _ := m.Value.(jsonObject)
+			out[i].Value = child.with(path[1:], value)
+		}
+		return out
+	}
+	if len(path) == 1 {
+		return append(out, jsonMember{Key: path[0], Value: value})
+	}
+	return append(out, jsonMember{Key: path[0], Value: jsonObject{}.with(path[1:], value)})
+}
+
+type settingKind int
+
+const (
+	settingBool settingKind = iota
+	settingInt
+	settingEnum
+	settingList
+	settingText
+)
+
+// settingField describes one editable value in settings.json
+type settingField struct {
+	section string
+	path    []string
+	label   string
+	kind    settingKind
+	options []string // settingEnum
+	min     int      // settingInt
+	max     int
+	help    string
+	missing interface{} // Value shown when the key isn't in settings.json
+}
+
+func (f settingField) id() string { return strings.Join(f.path, ".") }
+
+func boolField(section, label, help string, path ...string) settingField {
+	return settingField{section: section, path: path, label: label, kind: settingBool, help: help}
+}
+
+func intField(section, label string, min, max int, help string, path ...string) settingField {
+	return settingField{section: section, path: path, label: label, kind: settingInt, min: min, max: max, help: help}
+}
+
+func enumField(section, label string, options []string, help string, path ...string) settingField {
+	return settingField{section: section, path: path, label: label, kind: settingEnum, options: options, help: help}
+}
+
+// textField is free text, for values nothing in the TUI interprets
+func textField(section, label, help string, path ...string) settingField {
+	return settingField{section: section, path: path, label: label, kind: settingText, help: help}
+}
+
+func listField(section, label, help string, path ...string) settingField {
+	return settingField{section: section, path: path, label: label, kind: settingList, help: help}
+}
+
+var checkpointKeys = []struct{ key, name string }{
+	{"checkpoint_1_post_classification", "Post-classification review"},
+	{"checkpoint_2_post_context_gathering", "Post-context-gathering review"},
+	{"checkpoint_3_investigation_validation", "Investigation validation"},
+	{"checkpoint_4_solution_check", "Solution check"},
+}
+
+// settingsSchema lists the fields the editor shows, in display order.
+// Keys in settings.json that aren't listed are kept as they are.
+func settingsSchema() []settingField {
+	var fields []settingField
+	for _, cp := range checkpointKeys {
+		fields = append(fields, boolField("Checkpoints", cp.name, "Pause for human review at this point. Mandatory checkpoints can't be turned off.",
+			"checkpoints", cp.key, "enabled"))
+	}
+	fields = append(fields,
+		intField("Concurrency", "Max active investigations", 1, 10, "Investigations that may run at once (1-10)",
+			"concurrency", "max_active_investigations"),
+		textField("Concurrency", "Queue behavior", "What happens to queued investigations when a slot frees up, e.g. auto_start",
+			"concurrency", "queue_behavior"),
+
+		enumField("Agent mode", "Default mode", []string{"team", "single"}, "Team runs one agent per source; single runs one agent for everything",
+			"agent_mode", "default"),
+		boolField("Agent mode", "Fall back to single agent", "Retry in single-agent mode when team mode fails",
+			"agent_mode", "fallback_to_single"),
+		intField("Agent mode", "Single agent timeout (s)", 60, 7200, "Seconds before a single-agent run is stopped (60-7200)",
+			"agent_mode", "single_agent_timeout_seconds"),
+
+		boolField("Auto-proceed", "Enabled by default", "Continue past checkpoints automatically after the timeout",
+			"auto_proceed", "default_enabled"),
+		intField("Auto-proceed", "Timeout (s)", 30, 86400, "Seconds to wait at a checkpoint before proceeding (30-86400)",
+			"auto_proceed", "timeout_seconds"),
+		intField("Auto-proceed", "Warning threshold (s)", 0, 86400, "Warn this many seconds before auto-proceeding; must be less than the timeout",
+			"auto_proceed", "warning_threshold_seconds"),
+
+		intField("Code review", "Default depth", 1, 3, "How deep the codebase agent reads (1-3)",
+			"code_review", "default_depth"),
+		boolField("Code review", "Always prompt for level 3", "Ask before running the deepest code review",
+			"code_review", "always_prompt_for_level_3"),
+		boolField("Code review", "Auto level 3", "Escalate to level 3 without asking",
+			"code_review", "auto_level_3"),
+
+		intField("Timeouts", "Investigation max duration (s)", 60, 86400, "Seconds before an investigation is considered stuck (60-86400)",
+			"timeouts", "investigation_max_duration_seconds"),
+		intField("Timeouts", "Agent idle timeout (s)", 30, 86400, "Seconds without agent output before it's considered stalled; must be less than the max duration",
+			"timeouts", "agent_idle_timeout_seconds"),
+
+		boolField("Slack search", "Broad search", "Search all channels, not just priority ones",
+			"slack_search", "broad_search_enabled"),
+		boolField("Slack search", "Track channel frequency", "Remember which channels produce findings",
+			"slack_search", "track_channel_frequency"),
+		listField("Slack search", "Priority channels", "Comma-separated channel names, e.g. #support, #eng-oncall",
+			"slack_search", "priority_channels"),
+
+		boolField("Priority handling", "Read from Pylon", "Use the ticket priority set in Pylon",
+			"priority_handling", "read_from_pylon"),
+		boolField("Priority handling", "Suggest changes", "Suggest a different priority when findings warrant it",
+			"priority_handling", "suggest_changes"),
+		boolField("Priority handling", "Auto assign", "Apply suggested priorities without asking",
+			"priority_handling", "auto_assign"),
+		boolField("Priority handling", "Use Notion severity definitions", "Classify severity with the Notion definitions page",
+			"priority_handling", "use_notion_severity_definitions"),
+
+		textField("Customer response style", "Tone", "Voice used for drafted customer responses, e.g. friendly-professional",
+			"customer_response_style", "tone"),
+		listField("Customer response style", "Always include", "Comma-separated items every response must contain",
+			"customer_response_style", "always_include"),
+		listField("Customer response style", "Never do", "Comma-separated phrases responses must not contain; /regex/ for a pattern",
+			"customer_response_style", "never_do"),
+		intField("Customer response style", "Max paragraph words", 20, 1000, "Longer paragraphs are flagged when a response is linted",
+			"customer_response_style", "max_paragraph_words"),
+		boolField("Customer response style", "Learn from edits", "Record edits to drafted responses as examples",
+			"customer_response_style", "learn_from_edits"),
+
+		boolField("Safety", "C1 read-only", "Agents may only read from C1",
+			"safety", "c1_readonly"),
+		boolField("Safety", "Approve Linear actions", "Require approval before filing or updating Linear issues",
+			"safety", "require_human_approval_for_linear"),
+		boolField("Safety", "Approve Pylon actions", "Require approval before posting to Pylon",
+			"safety", "require_human_approval_for_pylon"),
+		boolField("Safety", "Approve Notion actions", "Require approval before writing to Notion",
+			"safety", "require_human_approval_for_notion"),
+		boolField("Safety", "Approve Slack actions", "Require approval before posting to Slack",
+			"safety", "require_human_approval_for_slack"),
+		enumField("Safety", "Approval method", []string{approvalConfirm, approvalTypeTicket, approvalSecondReviewer}, "How outward actions are approved",
+			"safety", "approval_method"),
+	)
+	// Match SafetySettings: a missing approval flag means approval is required
+	for i, f := range fields {
+		switch {
+		case f.path[0] == "safety" && strings.HasPrefix(f.path[1], "require_human_approval_for_"):
+			fields[i].missing = true
+		case f.id() == "safety.approval_method":
+			fields[i].missing = approvalTypeTicket
+		case f.id() == "customer_response_style.max_paragraph_words":
+			fields[i].missing = defaultMaxParagraphWords
+		}
+	}
+	return fields
+}
+
+// settingsEditor is the state of the settings screen
+type settingsEditor struct {
+	fields   []settingField
+	original jsonObject
+	values   []string // Current value of each field as text
+	loaded   []string // Values as loaded, for the diff
+	errors   map[int]string
+	cursor   int
+	input    textinput.Model
+	editing  bool // Text input open on the cursor field
+	review   bool // Showing the diff before saving
+	loading  bool
+	saving   bool
+	loadErr  error
+	saveErr  error
+}
+
+func newSettingsEditor() settingsEditor {
+	input := textinput.New()
+	input.Prompt = ""
+	return settingsEditor{fields: settingsSchema(), input: input, loading: true, errors: map[int]string{}}
+}
+
+// formatSettingValue turns a JSON value into the text the form edits
+func formatSettingValue(f settingField, v interface{}) string {
+	switch f.kind {
+	case settingBool:
+		b, _ := v.(bool)
+		return strconv.FormatBool(b)
+	case settingList:
+		items, _ := v.([]interface{})
+		var parts []string
+		for _, it := range items {
+			parts = append(parts, fmt.Sprint(it))
+		}
+		return strings.Join(parts, ", ")
+	}
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// parseSettingValue converts form text back to JSON, validating it
+func parseSettingValue(f settingField, s string) (interface{}, error) {
+	s = strings.TrimSpace(s)
+	switch f.kind {
+	case settingBool:
+		return s == "true", nil
+	case settingInt:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("must be a whole number")
+		}
+		if n < f.min || n > f.max {
+			return nil, fmt.Errorf("must be between %d and %d", f.min, f.max)
+		}
+		return json.Number(strconv.Itoa(n)), nil
+	case settingEnum:
+		for _, o := range f.options {
+			if o == s {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("must be one of %s", strings.Join(f.options, ", "))
+	case settingList:
+		items := []interface{}{}
+		for _, part := range strings.Split(s, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if f.id() == "slack_search.priority_channels" && strings.ContainsAny(part, " \t") {
+				return nil, fmt.Errorf("channel %q can't contain spaces", part)
+			}
+			items = append(items, part)
+		}
+		return items, nil
+	}
+	return s, nil
+}
+
+// load fills the form from a settings document
+func (e *settingsEditor) load(doc jsonObject) {
+	e.original = doc
+	e.values = make([]string, len(e.fields))
+	for i, f := range e.fields {
+		v, ok := doc.lookup(f.path)
+		if !ok || v == nil {
+			v = f.missing
+		}
+		if v == nil && f.kind == settingEnum && len(f.options) > 0 {
+			v = f.options[0]
+		}
+		if f.path[0] == "checkpoints" {
+			if name, ok := doc.lookup([]string{"checkpoints", f.path[1], "name"}); ok {
+				e.fields[i].label = fmt.Sprint(name)
+			}
+		}
+		e.values[i] = formatSettingValue(f, v)
+	}
+	e.loaded = append([]string(nil), e.values...)
+	e.errors = map[int]string{}
+	e.loading, e.loadErr, e.saveErr = false, nil, nil
+	e.cursor, e.editing, e.review = 0, false, false
+}
+
+func (e settingsEditor) indexOf(id string) int {
+	for i, f := range e.fields {
+		if f.id() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (e settingsEditor) intValue(id string) (int, bool) {
+	i := e.indexOf(id)
+	if i < 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(e.values[i]))
+	return n, err == nil
+}
+
+// validate checks every field plus the rules that span fields, returning
+// problems by field index.
+func (e settingsEditor) validate() map[int]string {
+	problems := map[int]string{}
+	for i, f := range e.fields {
+		if _, err := parseSettingValue(f, e.values[i]); err != nil {
+			problems[i] = err.Error()
+		}
+	}
+	for i, f := range e.fields {
+		if f.path[0] != "checkpoints" || e.values[i] == "true" {
+			continue
+		}
+		if mandatory, _ := e.original.lookup([]string{"checkpoints", f.path[1], "mandatory"}); mandatory == true {
+			problems[i] = "mandatory checkpoint; can't be disabled"
+		}
+	}
+	if warn, ok := e.intValue("auto_proceed.warning_threshold_seconds"); ok {
+		if timeout, ok := e.intValue("auto_proceed.timeout_seconds"); ok && warn >= timeout {
+			problems[e.indexOf("auto_proceed.warning_threshold_seconds")] = fmt.Sprintf("must be less than the timeout (%ds)", timeout)
+		}
+	}
+	if idle, ok := e.intValue("timeouts.agent_idle_timeout_seconds"); ok {
+		if maxDuration, ok := e.intValue("timeouts.investigation_max_duration_seconds"); ok && idle >= maxDuration {
+			problems[e.indexOf("timeouts.agent_idle_timeout_seconds")] = fmt.Sprintf("must be less than the max duration (%ds)", maxDuration)
+		}
+	}
+	return problems
+}
+
+// changes lists the fields whose values differ from what was loaded
+func (e settingsEditor) changes() []int {
+	var changed []int
+	for i := range e.fields {
+		if e.values[i] != e.loaded[i] {
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
+
+// document applies the form to the loaded settings. Call after validate.
+func (e settingsEditor) document() jsonObject {
+	doc := e.original
+	for _, i := range e.changes() {
+		v, _ := parseSettingValue(e.fields[i], e.values[i])
+		doc = doc.with(e.fields[i].path, v)
+	}
+	return doc
+}
+
+// Fetch settings.json through the API for editing
+func loadSettingsDocCmd() tea.Cmd {
+	return func() tea.Msg {
+		var doc jsonObject
+		if err := api.get(context.Background(), "/api/settings", &doc); err != nil {
+			return settingsDocLoadedMsg{err: err}
+		}
+		return settingsDocLoadedMsg{doc: doc}
+	}
+}
+
+// Save the whole settings document; the server replaces settings.json
+func saveSettingsDocCmd(doc jsonObject) tea.Cmd {
+	return func() tea.Msg {
+		if err := api.put(context.Background(), "/api/settings", doc, nil); err != nil {
+			return settingsSavedMsg{err: err}
+		}
+		var s Settings
+		raw, _ := json.Marshal(doc)
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return settingsSavedMsg{err: err}
+		}
+		return settingsSavedMsg{settings: &s}
+	}
+}
+
+// openSettings shows the settings screen and loads the current values
+func (m *model) openSettings() tea.Cmd {
+	m.settingsEdit = newSettingsEditor()
+	m.pushModal(modalSettings)
+	return loadSettingsDocCmd()
+}
+
+func (m model) handleSettingsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	e := &m.settingsEdit
+	if e.loading || e.saving {
+		if key.Matches(msg, keys.Escape) {
+			m.closeModal(modalSettings)
+		}
+		return m, nil
+	}
+	if e.loadErr != nil {
+		switch {
+		case key.Matches(msg, keys.Refresh):
+			return m, m.openSettings()
+		case key.Matches(msg, keys.Escape):
+			m.closeModal(modalSettings)
+		}
+		return m, nil
+	}
+
+	// Reviewing the diff: confirm or go back
+	if e.review {
+		switch {
+		case key.Matches(msg, keys.Yes), key.Matches(msg, keys.Enter):
+			e.saving = true
+			e.saveErr = nil
+			return m, saveSettingsDocCmd(e.document())
+		case key.Matches(msg, keys.No), key.Matches(msg, keys.Escape):
+			e.review = false
+		}
+		return m, nil
+	}
+
+	field := e.fields[e.cursor]
+
+	// Text input for ints and lists
+	if e.editing {
+		switch {
+		case key.Matches(msg, keys.Enter):
+			e.values[e.cursor] = strings.TrimSpace(e.input.Value())
+			e.editing = false
+			e.input.Blur()
+			e.errors = e.validate()
+			return m, nil
+		case key.Matches(msg, keys.Escape):
+			e.editing = false
+			e.input.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		e.input, cmd = e.input.Update(msg)
+		return m, cmd
+	}
+
+	switch {
+	case key.Matches(msg, keys.Escape):
+		if len(e.changes()) > 0 {
+			m.pushModal(modalConfirm)
+			m.confirmAction = "discard_settings"
+			m.confirmMessage = fmt.Sprintf("Discard %d unsaved settings change(s)?", len(e.changes()))
+			return m, nil
+		}
+		m.closeModal(modalSettings)
+		return m, nil
+
+	case key.Matches(msg, keys.Up):
+		if e.cursor > 0 {
+			e.cursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Down):
+		if e.cursor < len(e.fields)-1 {
+			e.cursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Save):
+		e.errors = e.validate()
+		if len(e.errors) > 0 {
+			// Jump to the first problem
+			var idx []int
+			for i := range e.errors {
+				idx = append(idx, i)
+			}
+			sort.Ints(idx)
+			e.cursor = idx[0]
+			return m, m.notify(severityWarning, "settings", fmt.Sprintf("Fix %d invalid setting(s) before saving", len(e.errors)), nil)
+		}
+		if len(e.changes()) == 0 {
+			return m, m.notify(severityInfo, "settings", "No changes to save", nil)
+		}
+		e.review = true
+		return m, nil
+
+	case msg.String() == "u":
+		// Undo the change on this field
+		e.values[e.cursor] = e.loaded[e.cursor]
+		e.errors = e.validate()
+		return m, nil
+
+	case key.Matches(msg, keys.Enter), msg.String() == " ", msg.String() == "left", msg.String() == "right", msg.String() == "h", msg.String() == "l":
+		switch field.kind {
+		case settingBool:
+			if e.values[e.cursor] == "true" {
+				e.values[e.cursor] = "false"
+			} else {
+				e.values[e.cursor] = "true"
+			}
+			e.errors = e.validate()
+		case settingEnum:
+			step := 1
+			if msg.String() == "left" || msg.String() == "h" {
+				step = len(field.options) - 1
+			}
+			idx := 0
+			for i, o := range field.options {
+				if o == e.values[e.cursor] {
+					idx = (i + step) % len(field.options)
+				}
+			}
+			e.values[e.cursor] = field.options[idx]
+			e.errors = e.validate()
+		default:
+			if !key.Matches(msg, keys.Enter) {
+				return m, nil
+			}
+			e.editing = true
+			e.input.SetValue(e.values[e.cursor])
+			e.input.CursorEnd()
+			return m, e.input.Focus()
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m model) renderSettingsEditor() string {
+	e := m.settingsEdit
+	dialogWidth := m.modalWidth(100)
+	dialogHeight := m.modalHeight(40)
+	inner := dialogWidth - 6
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(c1Primary).Render("Settings")
+	status := dimmedTextStyle.Render("settings.json via " + apiBase)
+	if n := len(e.changes()); !e.loading && e.loadErr == nil && n > 0 {
+		status = lipgloss.NewStyle().Foreground(statusWaiting).Render(fmt.Sprintf("%d unsaved change(s)", n))
+	}
+	header := title + "  " + status
+
+	var body, footer string
+	switch {
+	case e.loading:
+		body = m.spinner.View() + " Loading settings..."
+		footer = "Esc: close"
+	case e.loadErr != nil:
+		body = lipgloss.NewStyle().Foreground(statusError).Width(inner).Render("Couldn't load settings: " + e.loadErr.Error())
+		footer = "r: retry • Esc: close"
+	case e.review:
+		body = e.renderDiff(inner)
+		footer = "y/Enter: save • n/Esc: back to editing"
+		if e.saving {
+			footer = m.spinner.View() + " Saving..."
+		}
+	default:
+		body = e.renderFields(inner, dialogHeight-10)
+		switch {
+		case e.editing:
+			footer = "Enter: set • Esc: cancel"
+		default:
+			footer = "↑↓: move • Enter/Space: toggle or edit • ←→: options • u: undo field • Ctrl+S: review & save • Esc: close"
+		}
+	}
+	if e.saveErr != nil {
+		body += "\n\n" + lipgloss.NewStyle().Foreground(statusError).Width(inner).Render("Save failed: "+e.saveErr.Error())
+	}
+
+	content := lipgloss.NewStyle().Height(dialogHeight - 6).MaxHeight(dialogHeight - 6).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", body))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(c1Primary).
+		BorderBackground(bgPrimary).
+		Background(bgPrimary).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(lipgloss.JoinVertical(lipgloss.Left, content, dimmedTextStyle.Render(truncateStr(footer, inner))))
+}
+
+// renderFields draws the form grouped by section, scrolled to the cursor,
+// with the cursor field's help text underneath.
+func (e settingsEditor) renderFields(width, height int) string {
+	labelWidth := 34
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(textPrimary)
+	errorStyle := lipgloss.NewStyle().Foreground(statusError)
+	changedStyle := lipgloss.NewStyle().Foreground(statusWaiting)
+
+	var rows []string
+	cursorRow := 0
+	section := ""
+	for i, f := range e.fields {
+		if f.section != section {
+			if section != "" {
+				rows = append(rows, "")
+			}
+			section = f.section
+			rows = append(rows, sectionStyle.Render(strings.ToUpper(section)))
+		}
+
+		var value string
+		switch {
+		case e.editing && i == e.cursor:
+			e.input.Width = width - labelWidth - 4
+			value = e.input.View()
+		case f.kind == settingBool && e.values[i] == "true":
+			value = "[x] on"
+		case f.kind == settingBool:
+			value = "[ ] off"
+		case f.kind == settingEnum:
+			value = "< " + e.values[i] + " >"
+		case f.kind == settingList && e.values[i] == "":
+			value = dimmedTextStyle.Render("(none)")
+		default:
+			value = e.values[i]
+		}
+
+		marker := "  "
+		if e.values[i] != e.loaded[i] {
+			marker = changedStyle.Render("* ")
+		}
+		label := fmt.Sprintf("%-*s", labelWidth, truncateStr(f.label, labelWidth))
+		row := marker + label + truncateStr(value, width-labelWidth-2)
+		if i == e.cursor {
+			cursorRow = len(rows)
+			if !e.editing {
+				row = marker + selectedItemStyle.UnsetPadding().Render(label) + truncateStr(value, width-labelWidth-2)
+			}
+		}
+		rows = append(rows, row)
+		if problem, ok := e.errors[i]; ok {
+			rows = append(rows, strings.Repeat(" ", labelWidth+2)+errorStyle.Render(problem))
+		}
+	}
+
+	// Keep the cursor row in view, leaving two lines for the help text
+	visible := height - 2
+	if visible < 3 {
+		visible = 3
+	}
+	start := 0
+	if cursorRow >= visible-1 {
+		start = cursorRow - visible + 2
+	}
+	end := start + visible
+	if end > len(rows) {
+		end = len(rows)
+	}
+	help := dimmedTextStyle.Render(truncateStr(e.fields[e.cursor].id()+" — "+e.fields[e.cursor].help, width))
+	return lipgloss.NewStyle().Height(visible).Render(strings.Join(rows[start:end], "\n")) + "\n\n" + help
+}
+
+// renderDiff lists each changed setting as old → new
+func (e settingsEditor) renderDiff(width int) string {
+	removed := lipgloss.NewStyle().Foreground(statusError)
+	added := lipgloss.NewStyle().Foreground(statusCompleted)
+	lines := []string{lipgloss.NewStyle().Bold(true).Render("Review changes before saving"), ""}
+	for _, i := range e.changes() {
+		f := e.fields[i]
+		old, cur := e.loaded[i], e.values[i]
+		if old == "" {
+			old = "(none)"
+		}
+		if cur == "" {
+			cur = "(none)"
+		}
+		lines = append(lines,
+			f.section+" > "+f.label+dimmedTextStyle.Render("  "+f.id()),
+			removed.Render(truncateStr("  - "+old, width)),
+			added.Render(truncateStr("  + "+cur, width)),
+			"")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// snippetsDir holds reusable response snippets, one Markdown file each,
+// named after the file (e.g. "workaround-offered.md")
+var snippetsDir = filepath.Join(filepath.Dir(investigationsDir), "templates", "snippets")
+
+// Snippet is a reusable piece of customer response text. The body may use
+// {{variables}}, filled in from the ticket when it is inserted.
+type Snippet struct {
+	Name string
+	Path string
+	Body string
+}
+
+// snippetVarPattern matches {{customer_name}} and friends
+var snippetVarPattern = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// snippetVarNames are the variables a snippet can use, in the order they are
+// substituted back when a snippet is created from selected text
+var snippetVarNames = []string{"customer_name", "connector_name", "product_area", "ticket_title", "ticket_id"}
+
+// Load every snippet in dir, sorted by name. A missing directory just
+// means there are no snippets yet.
+func loadSnippetsCmd(dir string) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			return snippetsLoadedMsg{}
+		}
+		if err != nil {
+			return snippetsLoadedMsg{err: err}
+		}
+		var snippets []Snippet
+		var errs []error
+		for _, e := range entries {
+			if e.IsDir() || filepath.Ext(e.Name()) != ".md" {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			content, err := os.ReadFile(path)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			name := strings.ReplaceAll(strings.TrimSuffix(e.Name(), ".md"), "-", " ")
+			snippets = append(snippets, Snippet{Name: name, Path: path, Body: strings.TrimRight(string(content), "\n")})
+		}
+		sort.Slice(snippets, func(i, j int) bool { return snippets[i].Name < snippets[j].Name })
+		return snippetsLoadedMsg{snippets: snippets, err: errors.Join(errs...)}
+	}
+}
+
+// snippetFileName turns a snippet name into its file name
+func snippetFileName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-") + ".md"
+}
+
+// Write a new snippet, refusing to replace one with the same name
+func saveSnippetCmd(dir, name, body string) tea.Cmd {
+	return func() tea.Msg {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return snippetSavedMsg{name: name, err: err}
+		}
+		path := filepath.Join(dir, snippetFileName(name))
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, os.ErrExist) {
+			return snippetSavedMsg{name: name, err: fmt.Errorf("%s already exists", path)}
+		}
+		if err != nil {
+			return snippetSavedMsg{name: name, err: err}
+		}
+		_, err = f.WriteString(body + "\n")
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return snippetSavedMsg{name: name, path: path, err: err}
+	}
+}
+
+// snippetVars are the values for an investigation, taken from its
+// ticket-data.json where loaded and the investigation row otherwise
+func (m model) snippetVars(inv *Investigation) map[string]string {
+	vars := map[string]string{
+		"customer_name":  inv.CustomerName,
+		"connector_name": inv.ConnectorName,
+		"product_area":   inv.ProductArea,
+		"ticket_id":      strconv.Itoa(inv.ID),
+	}
+	if td := m.ticketData[inv.ID]; td != nil {
+		if td.CustomerName != "" {
+			vars["customer_name"] = td.CustomerName
+		}
+		if td.ConnectorName != nil && *td.ConnectorName != "" {
+			vars["connector_name"] = *td.ConnectorName
+		}
+		if td.ProductArea != "" {
+			vars["product_area"] = td.ProductArea
+		}
+		if td.TicketID != 0 {
+			vars["ticket_id"] = strconv.Itoa(td.TicketID)
+		}
+		vars["ticket_title"] = td.Title
+	}
+	return vars
+}
+
+// expandSnippet fills in a snippet's variables. Unknown or empty ones are
+// left as they are so the response linter flags them as placeholders.
+func expandSnippet(body string, vars map[string]string) string {
+	return snippetVarPattern.ReplaceAllStringFunc(body, func(match string) string {
+		name := snippetVarPattern.FindStringSubmatch(match)[1]
+		if v := vars[name]; v != "" {
+			return v
+		}
+		return match
+	})
+}
+
+// templatizeSnippet is the reverse of expandSnippet: it swaps this
+// ticket's values in selected text for variables so the snippet can be
+// reused on other tickets.
+func templatizeSnippet(text string, vars map[string]string) string {
+	for _, name := range snippetVarNames {
+		v := vars[name]
+		if len(v) < 2 {
+			continue
+		}
+		pattern := `(?i)\b` + regexp.QuoteMeta(v) + `\b`
+		if name == "ticket_id" {
+			pattern = `\b` + regexp.QuoteMeta(v) + `\b`
+		}
+		text = regexp.MustCompile(pattern).ReplaceAllString(text, "{{"+name+"}}")
+	}
+	return text
+}
+
+// snippetMatch is a snippet that matched the picker's query
+type snippetMatch struct {
+	snippet *Snippet
+	score   int
+}
+
+// snippetMatches filters the snippets by the picker's query, best first.
+// Names are matched fuzzily; bodies only by substring, and rank lower.
+func (m model) snippetMatches() []snippetMatch {
+	query := strings.TrimSpace(m.snippetQuery.Value())
+	var matches []snippetMatch
+	for i := range m.snippets {
+		s := &m.snippets[i]
+		if score, ok := fuzzyMatch(query, s.Name); ok {
+			matches = append(matches, snippetMatch{s, score + 1000})
+		} else if strings.Contains(strings.ToLower(s.Body), strings.ToLower(query)) {
+			matches = append(matches, snippetMatch{s, 0})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	return matches
+}
+
+// openSnippetPicker opens the picker over the response editor, reloading
+// the library so snippets added on disk show up
+func (m *model) openSnippetPicker() tea.Cmd {
+	m.snippetQuery.SetValue("")
+	m.snippetCursor = 0
+	m.pushModal(modalSnippets)
+	return tea.Batch(loadSnippetsCmd(snippetsDir), m.snippetQuery.Focus())
+}
+
+func (m model) handleSnippetPickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	matches := m.snippetMatches()
+	switch {
+	case key.Matches(msg, keys.Escape):
+		m.snippetQuery.Blur()
+		m.closeModal(modalSnippets)
+		return m, nil
+
+	case msg.Type == tea.KeyUp, msg.String() == "ctrl+p":
+		if m.snippetCursor > 0 {
+			m.snippetCursor--
+		}
+		return m, nil
+
+	case msg.Type == tea.KeyDown, msg.String() == "ctrl+n":
+		if m.snippetCursor < len(matches)-1 {
+			m.snippetCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Enter):
+		if m.snippetCursor >= len(matches) {
+			return m, nil
+		}
+		inv := m.getSelectedInvestigation()
+		if inv == nil || !m.editingResponse {
+			m.closeModal(modalSnippets)
+			return m, nil
+		}
+		m.responseTextarea.InsertString(expandSnippet(matches[m.snippetCursor].snippet.Body, m.snippetVars(inv)))
+		m.lintEditedResponse()
+		m.snippetQuery.Blur()
+		m.closeModal(modalSnippets)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.snippetQuery, cmd = m.snippetQuery.Update(msg)
+	m.snippetCursor = 0
+	return m, cmd
+}
+
+// startNewSnippet asks for a name for a snippet made from selected text
+func (m *model) startNewSnippet(text string) tea.Cmd {
+	inv := m.getSelectedInvestigation()
+	if inv == nil || strings.TrimSpace(text) == "" {
+		return nil
+	}
+	m.newSnippetBody = templatizeSnippet(strings.TrimSpace(text), m.snippetVars(inv))
+	m.newSnippetErr = nil
+	m.newSnippetName.SetValue("")
+	m.pushModal(modalNewSnippet)
+	return m.newSnippetName.Focus()
+}
+
+func (m model) handleNewSnippetKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Escape):
+		m.newSnippetName.Blur()
+		m.closeModal(modalNewSnippet)
+		return m, nil
+
+	case key.Matches(msg, keys.Enter):
+		name := strings.TrimSpace(m.newSnippetName.Value())
+		if snippetFileName(name) == ".md" {
+			m.newSnippetErr = errors.New("give the snippet a name")
+			return m, nil
+		}
+		return m, saveSnippetCmd(snippetsDir, name, m.newSnippetBody)
+	}
+
+	var cmd tea.Cmd
+	m.newSnippetName, cmd = m.newSnippetName.Update(msg)
+	m.newSnippetErr = nil
+	return m, cmd
+}
+
+func (m model) renderSnippetPicker() string {
+	dialogWidth := m.modalWidth(90)
+	inner := dialogWidth - 8
+	listHeight := m.modalHeight(30) / 3
+
+	header := lipgloss.NewStyle().Bold(true).Foreground(c1Primary).
+		Render(withIcon(glyphEdit, "Insert snippet"))
+
+	matches := m.snippetMatches()
+	var list string
+	switch {
+	case m.snippetsErr != nil && len(m.snippets) == 0:
+		list = lipgloss.NewStyle().Foreground(statusError).Width(inner).Render("Couldn't load snippets: " + m.snippetsErr.Error())
+	case len(m.snippets) == 0:
+		list = dimmedTextStyle.Width(inner).Render(fmt.Sprintf(
+			"No snippets yet. Add Markdown files to %s, or select text (v) and press n to save it as one.", snippetsDir))
+	case len(matches) == 0:
+		list = dimmedTextStyle.Render("No snippets match")
+	default:
+		start := 0
+		if m.snippetCursor >= listHeight {
+			start = m.snippetCursor - listHeight + 1
+		}
+		var lines []string
+		for i := start; i < len(matches) && i < start+listHeight; i++ {
+			s := matches[i].snippet
+			line := truncateStr(fmt.Sprintf("%-28s %s", truncateStr(s.Name, 28), firstLine(s.Body)), inner-2)
+			if i == m.snippetCursor {
+				lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(c1Primary).Render("▸ "+line))
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+		list = strings.Join(lines, "\n")
+	}
+
+	var preview string
+	if inv := m.getSelectedInvestigation(); inv != nil && m.snippetCursor < len(matches) {
+		body := expandSnippet(matches[m.snippetCursor].snippet.Body, m.snippetVars(inv))
+		lines := strings.Split(body, "\n")
+		if max := m.modalHeight(30) - listHeight - 12; max > 0 && len(lines) > max {
+			lines = append(lines[:max], "…")
+		}
+		preview = lipgloss.JoinVertical(lipgloss.Left,
+			sectionHeaderStyle.Render("PREVIEW"),
+			lipgloss.NewStyle().Foreground(textPrimary).Width(inner).Render(strings.Join(lines, "\n")))
+	}
+
+	footer := dimmedTextStyle.Render("Type to filter • ↑↓: select • Enter: insert at cursor • Esc: cancel")
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		header, "",
+		m.snippetQuery.View(), "",
+		list, "",
+		preview, "",
+		footer,
+	)
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(c1Primary).
+		BorderBackground(bgPrimary).
+		Background(bgPrimary).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(content)
+}
+
+func (m model) renderNewSnippetDialog() string {
+	dialogWidth := m.modalWidth(80)
+	inner := dialogWidth - 8
+
+	header := lipgloss.NewStyle().Bold(true).Foreground(c1Primary).
+		Render(withIcon(glyphSave, "Save selection as a snippet"))
+
+	lines := strings.Split(m.newSnippetBody, "\n")
+	if max := m.modalHeight(30) - 14; max > 0 && len(lines) > max {
+		lines = append(lines[:max], "…")
+	}
+	body := lipgloss.NewStyle().Foreground(textSecondary).Width(inner).Render(strings.Join(lines, "\n"))
+
+	parts := []string{header, "", "Name:", m.newSnippetName.View()}
+	if m.newSnippetErr != nil {
+		parts = append(parts, lipgloss.NewStyle().Foreground(statusError).Render(m.newSnippetErr.Error()))
+	}
+	parts = append(parts, "",
+		sectionHeaderStyle.Render("SNIPPET"),
+		body, "",
+		dimmedTextStyle.Width(inner).Render("This ticket's values were replaced with {{variables}} • Enter: save • Esc: cancel"),
+	)
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(c1Primary).
+		BorderBackground(bgPrimary).
+		Background(bgPrimary).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
@@ -124,6 +124,10 @@ var (
 	spinnerStyle = lipgloss.NewStyle().
 			Foreground(c1Primary)
 
+	// Layout behind an open dialog
+	modalBackdropStyle = lipgloss.NewStyle().
+				Foreground(textMuted)
+
 	// Debug overlay styles
 	debugOverlayStyle = lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
@@ -142,31 +146,31 @@ var (
 func getStatusIcon(status string) string {
 	switch status {
 	case "running":
-		return "🟡"
+		return glyph(glyphStatusRunning)
 	case "waiting":
-		return "🔵"
+		return glyph(glyphStatusWaiting)
 	case "completed", "complete":
-		return "🟢"
+		return glyph(glyphStatusCompleted)
 	case "error":
-		return "🔴"
-	case "pending":
-		return "○"
+		return glyph(glyphStatusError)
 	default:
-		return "○"
+		return glyph(glyphStatusPending)
 	}
 }
 
-func getAgentIcon(agentName string) string {
-	icons := map[string]string{
-		"Slack":    "💬",
-		"Linear":   "📋",
-		"Pylon":    "🎫",
-		"Codebase": "💻",
-	}
-	if icon, ok := icons[agentName]; ok {
-		return icon
+func agentGlyph(agentName string) glyphID {
+	switch agentName {
+	case "Slack":
+		return glyphAgentSlack
+	case "Linear":
+		return glyphAgentLinear
+	case "Pylon":
+		return glyphAgentPylon
+	case "Codebase":
+		return glyphAgentCodebase
+	default:
+		return glyphAgentOther
 	}
-	return "🔧"
 }
 
 func getTabName(tab TabType) string {
@@ -181,6 +185,8 @@ func getTabName(tab TabType) string {
 		return "Codebase"
 	case TabSummary:
 		return "Summary"
+	case TabLinearDraft:
+		return "Linear Draft"
 	case TabKB:
 		return "KB Article"
 	default:
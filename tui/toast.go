@@ -0,0 +1,298 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// severity ranks a notice; warnings and errors are kept in the error history
+type severity int
+
+const (
+	severityInfo severity = iota
+	severityWarning
+	severityError
+)
+
+func (s severity) String() string {
+	switch s {
+	case severityWarning:
+		return "warn"
+	case severityError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+func (s severity) color() lipgloss.Color {
+	switch s {
+	case severityWarning:
+		return statusRunning
+	case severityError:
+		return statusError
+	default:
+		return statusWaiting
+	}
+}
+
+// notice is a single toast / error history entry
+type notice struct {
+	id       int
+	severity severity
+	source   string // Command that produced it, e.g. "load agents"
+	message  string
+	at       time.Time
+	retry    tea.Cmd // Re-runs the failed command; nil when retrying makes no sense
+	retried  bool
+	count    int // Times it happened; repeats update the entry instead of adding one
+}
+
+// label is the message with its repeat count, e.g. "connection refused (x4)"
+func (n notice) label() string {
+	if n.count > 1 {
+		return fmt.Sprintf("%s (x%d)", n.message, n.count)
+	}
+	return n.message
+}
+
+const (
+	maxVisibleToasts = 3
+	maxErrorHistory  = 200
+	toastWidth       = 48
+)
+
+func toastLifetime(sev severity) time.Duration {
+	switch sev {
+	case severityWarning:
+		return 6 * time.Second
+	case severityError:
+		return 8 * time.Second
+	default:
+		return 3 * time.Second
+	}
+}
+
+// notify shows a toast and records warnings/errors in the history panel.
+// The returned command expires the toast. A repeat of a notice from the same
+// source bumps the existing entry's count rather than adding another, and
+// isn't toasted again while the first toast is still up.
+func (m *model) notify(sev severity, source, message string, retry tea.Cmd) tea.Cmd {
+	now := time.Now()
+	same := func(n notice) bool {
+		return n.severity == sev && n.source == source && n.message == message
+	}
+
+	if sev >= severityWarning {
+		merged := false
+		for i := len(m.errorLog) - 1; i >= 0; i-- {
+			if same(m.errorLog[i]) {
+				// Move it to the top with the latest retry
+				n := m.errorLog[i]
+				n.count++
+				n.at, n.retry, n.retried = now, retry, false
+				m.errorLog = append(append(m.errorLog[:i:i], m.errorLog[i+1:]...), n)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			m.errorLog = append(m.errorLog, notice{severity: sev, source: source, message: message, at: now, retry: retry, count: 1})
+			if len(m.errorLog) > maxErrorHistory {
+				m.errorLog = m.errorLog[len(m.errorLog)-maxErrorHistory:]
+			}
+		}
+	}
+
+	for i := range m.toasts {
+		if same(m.toasts[i]) {
+			m.toasts[i].count++
+			m.toasts[i].at, m.toasts[i].retry = now, retry
+			return nil
+		}
+	}
+
+	m.nextNoticeID++
+	n := notice{
+		id:       m.nextNoticeID,
+		severity: sev,
+		source:   source,
+		message:  message,
+		at:       now,
+		retry:    retry,
+		count:    1,
+	}
+	m.toasts = append(m.toasts, n)
+	if len(m.toasts) > maxVisibleToasts {
+		m.toasts = m.toasts[len(m.toasts)-maxVisibleToasts:]
+	}
+
+	id := n.id
+	return tea.Tick(toastLifetime(sev), func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+func (m *model) dismissToast(id int) {
+	kept := make([]notice, 0, len(m.toasts))
+	for _, t := range m.toasts {
+		if t.id != id {
+			kept = append(kept, t)
+		}
+	}
+	m.toasts = kept
+}
+
+func (m model) renderToast(n notice) string {
+	label := lipgloss.NewStyle().Bold(true).Foreground(n.severity.color()).
+		Render(strings.ToUpper(n.severity.String()))
+	source := ""
+	if n.source != "" {
+		source = dimmedTextStyle.Render(" " + n.source)
+	}
+
+	body := lipgloss.NewStyle().Foreground(textPrimary).Width(toastWidth - 4).
+		Render(truncateStr(n.label(), (toastWidth-4)*2))
+
+	lines := []string{label + source, body}
+	if n.severity >= severityWarning {
+		hint := "!: error history"
+		if n.retry != nil {
+			hint += " • retry available"
+		}
+		lines = append(lines, dimmedTextStyle.Render(hint))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(n.severity.color()).
+		Background(bgPrimary).
+		Padding(0, 1).
+		Width(toastWidth).
+		Render(strings.Join(lines, "\n"))
+}
+
+// overlayToasts stacks active toasts in the top-right corner, below the title
+func (m model) overlayToasts(frame string) string {
+	if len(m.toasts) == 0 {
+		return frame
+	}
+
+	var blocks []string
+	for i := len(m.toasts) - 1; i >= 0; i-- {
+		blocks = append(blocks, m.renderToast(m.toasts[i]))
+	}
+	stack := lipgloss.JoinVertical(lipgloss.Right, blocks...)
+
+	x := m.width - lipgloss.Width(stack) - 1
+	lines := overlayStyled(strings.Split(frame, "\n"), stack, x, 1)
+	return strings.Join(lines, "\n")
+}
+
+// handleErrorPanelKey handles navigation and retry in the error history panel
+func (m model) handleErrorPanelKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Escape), key.Matches(msg, keys.Errors):
+		m.closeModal(modalErrors)
+		return m, nil
+
+	case key.Matches(msg, keys.Up):
+		if m.errorPanelIndex > 0 {
+			m.errorPanelIndex--
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Down):
+		if m.errorPanelIndex < len(m.errorLog)-1 {
+			m.errorPanelIndex++
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Enter), key.Matches(msg, keys.Refresh):
+		// Entries are listed newest first
+		idx := len(m.errorLog) - 1 - m.errorPanelIndex
+		if idx < 0 || idx >= len(m.errorLog) || m.errorLog[idx].retry == nil {
+			return m, nil
+		}
+		m.errorLog[idx].retried = true
+		// A retry asked for by hand reports its failure again
+		delete(m.failingLoads, m.errorLog[idx].source)
+		retry := m.errorLog[idx].retry
+		toast := m.notify(severityInfo, m.errorLog[idx].source, "Retrying...", nil)
+		return m, tea.Batch(retry, toast)
+
+	case msg.String() == "x":
+		m.errorLog = nil
+		m.errorPanelIndex = 0
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m model) renderErrorPanel() string {
+	dialogWidth := m.modalWidth(90)
+	dialogHeight := m.modalHeight(24)
+
+	header := lipgloss.NewStyle().Bold(true).Foreground(statusError).
+		Render(fmt.Sprintf("Error History (%d)", len(m.errorLog)))
+
+	// Border, padding, header, blank line and footer take 7 rows
+	visible := dialogHeight - 7
+	if visible < 1 {
+		visible = 1
+	}
+
+	var rows []string
+	if len(m.errorLog) == 0 {
+		rows = append(rows, emptyStateStyle.Render("No errors this session"))
+	}
+
+	// Keep the selection in view
+	start := 0
+	if m.errorPanelIndex >= visible {
+		start = m.errorPanelIndex - visible + 1
+	}
+	for i := start; i < len(m.errorLog) && i < start+visible; i++ {
+		n := m.errorLog[len(m.errorLog)-1-i]
+		retry := " "
+		if n.retry != nil {
+			retry = "↻"
+			if n.retried {
+				retry = glyph(glyphDone)
+			}
+		}
+		line := fmt.Sprintf("%s %-5s %s %-22s %s",
+			n.at.Format("15:04:05"), n.severity.String(), retry, truncateStr(n.source, 22), n.label())
+		line = truncateStr(line, dialogWidth-6)
+		if i == m.errorPanelIndex {
+			line = selectedItemStyle.Render(line)
+		} else {
+			line = lipgloss.NewStyle().Foreground(n.severity.color()).Render(line)
+		}
+		rows = append(rows, line)
+	}
+
+	footer := dimmedTextStyle.Render("↑↓: select • Enter/r: retry • x: clear • Esc: close")
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		"",
+		strings.Join(rows, "\n"),
+	)
+	content = lipgloss.NewStyle().Height(dialogHeight - 5).Render(content)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(statusError).
+		BorderBackground(bgPrimary).
+		Background(bgPrimary).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(lipgloss.JoinVertical(lipgloss.Left, content, footer))
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// InvestigationVersion is a snapshot from version-manager.js. Each snapshot
+// holds every investigation file; only the customer response is used here.
+type InvestigationVersion struct {
+	ID          int    `json:"id"`
+	Number      int    `json:"version_number"`
+	RunNumber   int    `json:"run_number"`
+	Label       string `json:"label"`
+	Checkpoint  string `json:"checkpoint"`
+	DiffSummary string `json:"diff_summary"`
+	CreatedBy   string `json:"created_by"`
+	CreatedAt   string `json:"created_at"`
+	Files       string `json:"snapshot_files"` // JSON object of file name -> content
+
+	Response    string // customer-response.md at the time of the snapshot
+	HasResponse bool
+}
+
+// versionHistory is the timeline shown in place of the summary on the
+// Summary tab. It owns the keyboard while open.
+type versionHistory struct {
+	open            bool
+	investigationID int
+	cursor          int // Index into the versions, newest first
+	loading         bool
+	restoring       bool
+	err             error
+}
+
+// parseVersionFiles pulls the customer response out of the snapshot. Other
+// entries aren't all strings (.json files are stored parsed), so only the
+// response is decoded.
+func (v *InvestigationVersion) parseVersionFiles() {
+	var files map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(v.Files), &files); err != nil {
+		return
+	}
+	var content *string
+	if raw, ok := files["customer-response.md"]; ok && json.Unmarshal(raw, &content) == nil && content != nil {
+		v.Response, v.HasResponse = *content, true
+	}
+	v.Files = "" // Not needed once parsed, and can be large
+}
+
+// Load the investigation's version snapshots, newest first
+func loadVersionsCmd(ctx context.Context, investigationID, gen int) tea.Cmd {
+	return func() tea.Msg {
+		path := fmt.Sprintf("/api/investigations/%d/versions", investigationID)
+		var versions []InvestigationVersion
+		if err := api.get(ctx, path, &versions); err != nil {
+			if endpointMissing(err) {
+				err = fmt.Errorf("server has no %s endpoint; update the UI server", path)
+			}
+			return versionsLoadedMsg{investigationID: investigationID, err: err, gen: gen}
+		}
+		for i := range versions {
+			versions[i].parseVersionFiles()
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i].Number > versions[j].Number })
+		return versionsLoadedMsg{investigationID: investigationID, versions: versions, gen: gen}
+	}
+}
+
+// Roll the investigation back to a version. The server snapshots the
+// current state first, so the restore itself shows up in the history.
+func restoreVersionCmd(investigationID, versionID int) tea.Cmd {
+	return func() tea.Msg {
+		path := fmt.Sprintf("/api/investigations/%d/versions/restore", investigationID)
+		body := map[string]interface{}{"versionId": versionID, "mode": "rollback"}
+		var result struct {
+			Message string `json:"message"`
+		}
+		if err := api.post(context.Background(), path, body, &result); err != nil {
+			if endpointMissing(err) {
+				err = fmt.Errorf("server has no %s endpoint; update the UI server", path)
+			}
+			return versionRestoredMsg{investigationID: investigationID, err: err}
+		}
+		return versionRestoredMsg{investigationID: investigationID, message: result.Message}
+	}
+}
+
+func (m model) versionsCmd(investigationID int) tea.Cmd {
+	gen := m.loads.next(loadKey{kind: loadVersions, investigationID: investigationID})
+	return loadVersionsCmd(m.loads.ctx, investigationID, gen)
+}
+
+// openVersionHistory shows the timeline for the selected investigation
+func (m *model) openVersionHistory(inv *Investigation) tea.Cmd {
+	m.history = versionHistory{open: true, investigationID: inv.ID, loading: true}
+	return m.versionsCmd(inv.ID)
+}
+
+// selectedVersion is the version under the cursor
+func (m model) selectedVersion() *InvestigationVersion {
+	versions := m.versions[m.history.investigationID]
+	if m.history.cursor < 0 || m.history.cursor >= len(versions) {
+		return nil
+	}
+	return &versions[m.history.cursor]
+}
+
+func (m model) handleVersionHistoryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	versions := m.versions[m.history.investigationID]
+	switch {
+	case key.Matches(msg, keys.Escape), msg.String() == "h":
+		m.history.open = false
+		return m, nil
+
+	case key.Matches(msg, keys.Up):
+		if m.history.cursor > 0 {
+			m.history.cursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Down):
+		if m.history.cursor < len(versions)-1 {
+			m.history.cursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Refresh):
+		m.history.loading = true
+		m.history.err = nil
+		return m, m.versionsCmd(m.history.investigationID)
+
+	case key.Matches(msg, keys.Enter):
+		v := m.selectedVersion()
+		if v == nil || m.history.restoring {
+			return m, nil
+		}
+		m.pushModal(modalConfirm)
+		m.confirmAction = "restore_version"
+		m.confirmMessage = fmt.Sprintf("Roll #%d back to v%d (%s)?\n\n"+
+			"Every file in the snapshot is restored, not just the customer response, "+
+			"and the investigation returns to %s. The current state is saved as a new version first.",
+			m.history.investigationID, v.Number, m.versionTitle(v), checkpointName(v.Checkpoint))
+		if response := m.customerResponses[m.history.investigationID]; response != nil && v.HasResponse {
+			if m.confirmDiff = lineDiff(response.Content, v.Response, 2); m.confirmDiff != nil {
+				m.confirmMessage += "\n\nThe customer response changes like this:"
+			}
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// versionTitle is the version's label, falling back to its checkpoint
+func (m model) versionTitle(v *InvestigationVersion) string {
+	if v.Label != "" {
+		return v.Label
+	}
+	return checkpointName(v.Checkpoint)
+}
+
+// renderVersionHistory replaces the summary view: the timeline on top and
+// the selected version's response diffed against the current one below.
+func (m model) renderVersionHistory(inv *Investigation, width, height int) string {
+	innerWidth := width - 8
+	usableHeight := height - 6
+	listHeight := usableHeight / 3
+	if listHeight < 5 {
+		listHeight = 5
+	}
+	diffHeight := usableHeight - listHeight - 1
+
+	versions := m.versions[inv.ID]
+	header := sectionHeaderStyle.Render(fmt.Sprintf("VERSION HISTORY (%d)", len(versions)))
+
+	var list string
+	switch {
+	case m.history.err != nil:
+		list = lipgloss.NewStyle().Foreground(statusError).Width(innerWidth).Render("Couldn't load versions: " + m.history.err.Error())
+	case m.history.loading && len(versions) == 0:
+		list = m.spinner.View() + " Loading versions..."
+	case len(versions) == 0:
+		list = dimmedTextStyle.Render("No versions yet. Snapshots are taken at each checkpoint.")
+	default:
+		var current string
+		if response := m.customerResponses[inv.ID]; response != nil {
+			current = response.Content
+		}
+		// Keep the cursor in view
+		start := 0
+		if m.history.cursor >= listHeight {
+			start = m.history.cursor - listHeight + 1
+		}
+		end := start + listHeight
+		if end > len(versions) {
+			end = len(versions)
+		}
+		var lines []string
+		for i := start; i < end; i++ {
+			v := &versions[i]
+			when := v.CreatedAt
+			if t, err := parseServerTime(v.CreatedAt); err == nil {
+				when = t.Local().Format("Jan 2 15:04")
+			}
+			author := v.CreatedBy
+			if author == "" {
+				author = "system"
+			}
+			var note string
+			switch {
+			case !v.HasResponse:
+				note = "no response"
+			case strings.TrimSpace(v.Response) == strings.TrimSpace(current):
+				note = "= current"
+			case i+1 < len(versions) && versions[i+1].HasResponse && versions[i+1].Response != v.Response:
+				note = "response changed"
+			}
+			line := fmt.Sprintf("v%-3d %-12s %-8s %s", v.Number, when, truncateStr(author, 8), m.versionTitle(v))
+			if note != "" {
+				line += " • " + note
+			}
+			line = truncateStr(line, innerWidth-2)
+			if i == m.history.cursor {
+				lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(c1Primary).Render("▸ "+line))
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+		list = strings.Join(lines, "\n")
+	}
+	list = lipgloss.NewStyle().Width(innerWidth).Height(listHeight).Render(list)
+
+	var diffHeader, diff string
+	if v := m.selectedVersion(); v != nil && inv.ID == m.history.investigationID {
+		diffHeader = sectionHeaderStyle.Render(fmt.Sprintf("v%d → CURRENT RESPONSE", v.Number))
+		response := m.customerResponses[inv.ID]
+		switch {
+		case !v.HasResponse:
+			diff = dimmedTextStyle.Render("This version has no customer response.")
+		case response == nil:
+			diff = dimmedTextStyle.Render("There is no current customer response; restoring brings this one back.")
+		default:
+			// Removed lines are in the old version, added lines are current
+			diff = renderDiff(lineDiff(v.Response, response.Content, 2), innerWidth, diffHeight-2)
+		}
+	}
+	diff = lipgloss.NewStyle().Width(innerWidth).Height(diffHeight - 2).Render(diff)
+
+	footer := "↑↓: select • Enter: restore • r: refresh • Esc/h: close"
+	if m.history.restoring {
+		footer = m.spinner.View() + " Restoring..."
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		list,
+		lipgloss.NewStyle().Foreground(borderColor).Render(strings.Repeat("─", innerWidth)),
+		diffHeader,
+		diff,
+		dimmedTextStyle.Render(footer),
+	)
+	return contentPanelStyle.
+		Width(width - 4).
+		Height(height - 2).
+		Render(content)
+}
@@ -9,33 +9,21 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 )
 
+// View renders the current frame, downgrading it to plain ASCII when the
+// terminal can't be trusted with emoji or box-drawing widths.
 func (m model) View() string {
+	return toASCII(m.renderFrame())
+}
+
+func (m model) renderFrame() string {
+	// Only fatal errors get here; everything else is a toast
 	if m.err != nil {
 		return fmt.Sprintf("Error: %v\n\nPress q to quit.", m.err)
 	}
 
-	// Render reset form overlay if shown
-	if m.showResetForm {
-		return m.renderResetForm()
-	}
-
-	// Render reply prompt overlay if shown
-	if m.showReplyPrompt {
-		return m.renderReplyPrompt()
-	}
-
-	// Render confirmation dialog overlay if shown
-	if m.showConfirmDialog {
-		return m.renderConfirmDialog()
-	}
-
-	// Render create form overlay if shown
-	if m.showCreateForm {
-		return m.renderCreateForm()
-	}
-
 	// Title bar
 	title := titleStyle.Width(m.width).Render("Support Triage")
 
@@ -170,7 +158,10 @@ func (m model) View() string {
 	for len(lines) < m.height {
 		lines = append(lines, "")
 	}
-	return strings.Join(lines, "\n")
+
+	// Dialogs are composited over the finished layout so it stays visible,
+	// toasts go on top of everything
+	return m.overlayToasts(m.compositeModals(strings.Join(lines, "\n")))
 }
 
 func (m model) renderSidebar(width, height int) string {
@@ -186,10 +177,13 @@ func (m model) renderSidebar(width, height int) string {
 	for i, inv := range m.investigations {
 		statusIcon := getStatusIcon(inv.Status)
 		if inv.HasNewReply == 1 {
-			statusIcon = "📩"
+			statusIcon = glyph(glyphReply)
 		}
 
 		line := fmt.Sprintf("%s #%d - %s", statusIcon, inv.ID, inv.CustomerName)
+		if m.watchdog[inv.ID].flagged() {
+			line = fmt.Sprintf("%s%s #%d - %s", statusIcon, glyph(glyphStalled), inv.ID, inv.CustomerName)
+		}
 		meta := fmt.Sprintf("  %s • %s", inv.Classification, formatCheckpointShort(inv.Status, inv.CurrentCheckpoint, inv.CurrentRunNumber))
 
 		if i == m.selectedIndex {
@@ -222,36 +216,55 @@ func (m model) renderTabBar(width int) string {
 	tabs := []struct {
 		tab    TabType
 		name   string
-		icon   string
+		icon   glyphID
 		status string
 	}{
-		{TabSlack, "Slack", "💬", inv.AgentStatuses["Slack"]},
-		{TabLinear, "Linear", "📋", inv.AgentStatuses["Linear"]},
-		{TabPylon, "Pylon", "🎫", inv.AgentStatuses["Pylon"]},
-		{TabCodebase, "Codebase", "💻", inv.AgentStatuses["Codebase"]},
-		{TabSummary, "Summary", "📊", ""},
-		// {TabKB, "KB Article", "📝", ""}, // V2 feature
-	}
-
-	var renderedTabs []string
-	for _, tab := range tabs {
-		var style lipgloss.Style
-		if tab.tab == m.activeTab {
-			style = activeTabStyle
-		} else {
-			style = inactiveTabStyle
-		}
+		{TabSlack, "Slack", glyphAgentSlack, inv.AgentStatuses["Slack"]},
+		{TabLinear, "Linear", glyphAgentLinear, inv.AgentStatuses["Linear"]},
+		{TabPylon, "Pylon", glyphAgentPylon, inv.AgentStatuses["Pylon"]},
+		{TabCodebase, "Codebase", glyphAgentCodebase, inv.AgentStatuses["Codebase"]},
+		{TabSummary, "Summary", glyphTabSummary, ""},
+		{TabLinearDraft, "Linear Draft", glyphTabLinearDraft, linearDraftTabStatus(inv)},
+		{TabKB, "KB Article", glyphTabKB, ""},
+	}
+
+	render := func(compact bool) string {
+		var renderedTabs []string
+		for _, tab := range tabs {
+			var style lipgloss.Style
+			if tab.tab == m.activeTab {
+				style = activeTabStyle
+			} else {
+				style = inactiveTabStyle
+			}
 
-		statusIcon := getStatusIcon(tab.status)
-		if tab.status == "" {
-			statusIcon = ""
+			tabContent := withIcon(tab.icon, tab.name)
+			if compact && tab.tab != m.activeTab {
+				// Icon only; the active tab keeps its name
+				tabContent = glyph(tab.icon)
+				if tabContent == "" {
+					tabContent = tab.name[:1]
+				}
+			}
+			if tab.status != "" {
+				tabContent += " " + getStatusIcon(tab.status)
+			}
+			if _, stalled := m.watchdog[inv.ID].agent(tab.name); stalled {
+				tabContent += " " + glyph(glyphStalled)
+			}
+			if state := m.getAgentState(inv.ID, tab.name); state != nil && m.agentProcessGone(state) {
+				tabContent += " " + glyph(glyphFailed)
+			}
+			renderedTabs = append(renderedTabs, style.Render(tabContent))
 		}
-
-		tabContent := fmt.Sprintf("%s %s %s", tab.icon, tab.name, statusIcon)
-		renderedTabs = append(renderedTabs, style.Render(tabContent))
+		return lipgloss.JoinHorizontal(lipgloss.Top, renderedTabs...)
 	}
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, renderedTabs...)
+	bar := render(false)
+	if lipgloss.Width(bar) > width-4 {
+		bar = render(true)
+	}
+	return bar
 }
 
 func (m model) renderTabContent(width, height int) string {
@@ -267,17 +280,28 @@ func (m model) renderTabContent(width, height int) string {
 
 	// Show checkpoint banner for checkpoints 2-4
 	checkpointBanner := m.renderCheckpointBanner(inv, width)
+	if checkpointBanner == "" {
+		checkpointBanner = m.renderWatchdogBanner(inv, width)
+	}
 	bannerHeight := 0
 	if checkpointBanner != "" {
 		bannerHeight = lipgloss.Height(checkpointBanner)
 	}
 
 	var tabContent string
-	switch m.activeTab {
-	case TabSlack, TabLinear, TabPylon, TabCodebase:
+	switch {
+	case m.selecting:
+		tabContent = m.renderSelectionView(width, height-bannerHeight)
+	case m.activeTab == TabSlack, m.activeTab == TabLinear, m.activeTab == TabPylon, m.activeTab == TabCodebase:
 		tabContent = m.renderAgentView(width, height-bannerHeight)
-	case TabSummary:
+	case m.activeTab == TabSummary && m.history.open && m.history.investigationID == inv.ID:
+		tabContent = m.renderVersionHistory(inv, width, height-bannerHeight)
+	case m.activeTab == TabSummary:
 		tabContent = m.renderSummaryView(width, height-bannerHeight)
+	case m.activeTab == TabLinearDraft:
+		tabContent = m.renderLinearDraftView(width, height-bannerHeight)
+	case m.activeTab == TabKB:
+		tabContent = m.renderKBView(width, height-bannerHeight)
 	default:
 		tabContent = contentPanelStyle.
 			Width(width - 4).
@@ -356,7 +380,7 @@ func (m model) renderCheckpointBanner(inv *Investigation, width int) string {
 
 	banner := lipgloss.JoinVertical(
 		lipgloss.Left,
-		headerStyle.Render(fmt.Sprintf("⏸ %s", info.name)),
+		headerStyle.Render(withIcon(glyphPaused, info.name)),
 		"",
 		descStyle.Render(info.description),
 		nextStyle.Render(info.nextAction),
@@ -392,7 +416,7 @@ func (m model) renderCheckpointReview(inv *Investigation, width, height int) str
 		Background(bgTertiary).
 		Padding(0, 1).
 		Width(innerWidth)
-	sections = append(sections, headerStyle.Render("⏸ CLASSIFICATION REVIEW"))
+	sections = append(sections, headerStyle.Render(withIcon(glyphPaused, "CLASSIFICATION REVIEW")))
 	sections = append(sections, "")
 
 	// Ticket info
@@ -455,9 +479,7 @@ func (m model) renderCheckpointReview(inv *Investigation, width, height int) str
 	}
 	// Also truncate very long single lines
 	for i, line := range bodyLines {
-		if len(line) > innerWidth-4 {
-			bodyLines[i] = line[:innerWidth-7] + "..."
-		}
+		bodyLines[i] = truncateStr(line, innerWidth-4)
 	}
 	bodyStyle := lipgloss.NewStyle().Foreground(textSecondary).Width(innerWidth - 2).Padding(0, 1)
 	sections = append(sections, bodyStyle.Render(strings.Join(bodyLines, "\n")))
@@ -606,20 +628,26 @@ func (m model) renderAgentView(width, height int) string {
 			Render(placeholder)
 	}
 
-	// Agent status header
+	// Agent status header, plus the error when the agent failed
 	statusHeader := m.renderAgentStatusHeader(agentState, width)
+	errorDetails := m.renderAgentErrorDetails(agentState, width)
+	errorHeight := 0
+	if errorDetails != "" {
+		errorHeight = lipgloss.Height(errorDetails)
+	}
 
 	// Findings (40% height)
 	findingsHeight := int(float64(height) * 0.4)
 	findings := m.renderFindings(agentState, width, findingsHeight)
 
 	// Terminal output (60% height)
-	terminalHeight := height - findingsHeight - 8
+	terminalHeight := height - findingsHeight - 5 - lipgloss.Height(statusHeader) - errorHeight
 	terminal := m.renderTerminalOutput(agentState, width, terminalHeight)
 
 	content := lipgloss.JoinVertical(
 		lipgloss.Left,
 		statusHeader,
+		errorDetails,
 		findings,
 		terminal,
 	)
@@ -640,8 +668,22 @@ func (m model) renderAgentStatusHeader(state *AgentState, width int) string {
 		state.StartedAt.Format("15:04:05"),
 		state.Runtime.Round(time.Second).String(),
 	)
+	if !state.CompletedAt.IsZero() {
+		status += "    Finished: " + state.CompletedAt.Format("15:04:05")
+	}
 
-	return sectionHeaderStyle.Render(status)
+	hints := ""
+	if inv := m.getSelectedInvestigation(); inv != nil {
+		hints = m.agentControlHints(inv, state)
+	}
+	header := sectionHeaderStyle.Render(status)
+	if resources := m.renderAgentResources(state, width); resources != "" {
+		header += "\n" + resources
+	}
+	if hints != "" {
+		header += "\n" + dimmedTextStyle.Render("  "+truncateStr(hints, width-10))
+	}
+	return header
 }
 
 func (m model) renderFindings(state *AgentState, width, height int) string {
@@ -674,7 +716,7 @@ func (m model) renderFindings(state *AgentState, width, height int) string {
 	for i, finding := range state.Findings {
 		// Finding title with emoji
 		titleStyle := lipgloss.NewStyle().Bold(true).Foreground(c1Primary)
-		findingLines = append(findingLines, titleStyle.Render(fmt.Sprintf("📌 Finding #%d: %s", i+1, finding.Title)))
+		findingLines = append(findingLines, titleStyle.Render(withIcon(glyphFinding, fmt.Sprintf("Finding #%d: %s", i+1, finding.Title))))
 
 		// Details with bullet points
 		for _, detail := range finding.Details {
@@ -869,7 +911,7 @@ func (m model) renderSummarySection(inv *Investigation, summary *InvestigationSu
 	// Root cause
 	if summary.RootCause != "" {
 		sections = append(sections,
-			sectionHeaderStyle.Render("🔍 ROOT CAUSE"),
+			sectionHeaderStyle.Render(withIcon(glyphRootCause, "ROOT CAUSE")),
 			summary.RootCause,
 			"",
 		)
@@ -877,14 +919,13 @@ func (m model) renderSummarySection(inv *Investigation, summary *InvestigationSu
 
 	// Key findings from each agent
 	if len(summary.KeyFindings) > 0 {
-		sections = append(sections, sectionHeaderStyle.Render("📋 KEY FINDINGS"), "")
+		sections = append(sections, sectionHeaderStyle.Render(withIcon(glyphKeyFindings, "KEY FINDINGS")), "")
 
 		for _, agentName := range []string{"Slack", "Linear", "Pylon", "Codebase"} {
 			findings := summary.KeyFindings[agentName]
 			if len(findings) > 0 {
-				icon := getAgentIcon(agentName)
 				agentHeader := lipgloss.NewStyle().Bold(true).Foreground(c1Primary).Render(
-					fmt.Sprintf("%s %s", icon, agentName),
+					withIcon(agentGlyph(agentName), agentName),
 				)
 				sections = append(sections, agentHeader)
 
@@ -898,7 +939,7 @@ func (m model) renderSummarySection(inv *Investigation, summary *InvestigationSu
 
 	// Open questions
 	if len(summary.OpenQuestions) > 0 {
-		sections = append(sections, sectionHeaderStyle.Render("❓ OPEN QUESTIONS"), "")
+		sections = append(sections, sectionHeaderStyle.Render(withIcon(glyphQuestions, "OPEN QUESTIONS")), "")
 		for _, question := range summary.OpenQuestions {
 			sections = append(sections, fmt.Sprintf("   • %s", question))
 		}
@@ -907,7 +948,7 @@ func (m model) renderSummarySection(inv *Investigation, summary *InvestigationSu
 
 	// Next steps
 	if len(summary.NextSteps) > 0 {
-		sections = append(sections, sectionHeaderStyle.Render("➡️  NEXT STEPS"), "")
+		sections = append(sections, sectionHeaderStyle.Render(withIcon(glyphNextSteps, "NEXT STEPS")), "")
 		for i, step := range summary.NextSteps {
 			sections = append(sections, fmt.Sprintf("   %d. %s", i+1, step))
 		}
@@ -939,10 +980,12 @@ func (m model) renderResponseSection(inv *Investigation, response *CustomerRespo
 
 	// Show editing UI if in edit mode
 	if m.editingResponse {
-		editHeader := logCheckpointStyle.Render("✏️  EDITING MODE")
+		editHeader := logCheckpointStyle.Render(withIcon(glyphEdit, "EDITING MODE"))
 
-		// Update textarea dimensions - constrain to available height
-		taHeight := height - 5
+		// Update textarea dimensions - constrain to available height, leaving
+		// room for the style check
+		lintLines := lintHeight(m.editLint, (height-5)/3)
+		taHeight := height - 5 - lintLines
 		if taHeight < 3 {
 			taHeight = 3
 		}
@@ -950,47 +993,169 @@ func (m model) renderResponseSection(inv *Investigation, response *CustomerRespo
 		m.responseTextarea.SetHeight(taHeight)
 
 		textareaView := m.responseTextarea.View()
-		footer := dimmedTextStyle.Render("Ctrl+S: save • Esc: cancel")
+		footer := dimmedTextStyle.Render("Ctrl+S: save • Ctrl+O: insert snippet • Esc: cancel")
 
-		return lipgloss.JoinVertical(
-			lipgloss.Left,
-			header,
-			editHeader,
-			textareaView,
-			footer,
-		)
+		parts := []string{header, editHeader, textareaView}
+		if lintLines > 0 {
+			parts = append(parts, renderLint(m.editLint, width-8, lintLines))
+		}
+		parts = append(parts, footer)
+		return lipgloss.JoinVertical(lipgloss.Left, parts...)
+	}
+
+	findings := m.responseLints[inv.ID]
+	postHint := "[P] Post to Pylon"
+	if firstLintError(findings) != nil {
+		postHint = "[P] Post blocked by style check"
 	}
 
 	// Normal display mode
 	var footer string
 	if response.CopiedToClip {
-		footer = "✅ Copied! • [E] Edit • [C] Copy again • [P] Post to Pylon"
-	} else if response.PostedToPylon {
-		footer = "✅ Posted to Pylon! • [E] Edit • [C] Copy"
+		footer = withIcon(glyphDone, "Copied! • [E] Edit • [C] Copy again • "+postHint)
+	} else if response.PostedToPylon && response.Posted != nil {
+		footer = withIcon(glyphDone, fmt.Sprintf("Posted to Pylon %s (%s) • [E] Edit • [C] Copy",
+			response.Posted.PostedAt.Format("Jan 2 15:04"), response.Posted.MessageID))
+	} else if response.Posted != nil {
+		footer = fmt.Sprintf("Dry run saved %s • [E] Edit • [C] Copy • %s",
+			response.Posted.PostedAt.Format("Jan 2 15:04"), postHint)
 	} else {
-		footer = "[E] Edit • [C] Copy to clipboard • [P] Post to Pylon"
+		footer = "[E] Edit • [C] Copy to clipboard • " + postHint
 	}
 
-	// Constrain response content to available height
+	// Constrain response content to available height, below which go the
+	// style check findings
+	lintLines := lintHeight(findings, (height-3)/3)
 	contentView := lipgloss.NewStyle().
 		Width(width - 8).
-		Height(height - 3).
+		Height(height - 3 - lintLines).
 		Render(response.Content)
 
-	return lipgloss.JoinVertical(
+	parts := []string{header, contentView}
+	if lintLines > 0 {
+		parts = append(parts, renderLint(findings, width-8, lintLines))
+	}
+	parts = append(parts, dimmedTextStyle.Render(footer))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+func (m model) renderKBView(width, height int) string {
+	inv := m.getSelectedInvestigation()
+	if inv == nil {
+		return ""
+	}
+	panel := contentPanelStyle.Width(width - 4).Height(height - 2)
+
+	loaded, hasKey := m.kbArticles[inv.ID]
+	if !hasKey {
+		return panel.Render(fmt.Sprintf("%s Loading KB article...", m.spinner.View()))
+	}
+	if loaded == nil {
+		var hint string
+		switch {
+		case m.kbGenerating[inv.ID]:
+			hint = fmt.Sprintf("%s Generating a KB draft...", m.spinner.View())
+		case inv.Status == "complete":
+			hint = "No KB article for this investigation yet.\n\n[g] Generate a KB draft"
+		default:
+			hint = "No KB article for this investigation.\n\nA KB draft can be generated once the investigation completes."
+		}
+		return panel.Render(emptyStateStyle.Render(hint))
+	}
+	article := loaded
+
+	header := sectionHeaderStyle.Render(fmt.Sprintf("KB ARTICLE  %s",
+		dimmedTextStyle.Render("edited "+article.Modified.Format("Jan 2 15:04"))))
+
+	if m.editingKB {
+		taHeight := height - 8
+		if taHeight < 3 {
+			taHeight = 3
+		}
+		m.kbTextarea.SetWidth(width - 10)
+		m.kbTextarea.SetHeight(taHeight)
+		return panel.Render(lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			logCheckpointStyle.Render(withIcon(glyphEdit, "EDITING MODE")),
+			m.kbTextarea.View(),
+			dimmedTextStyle.Render("Ctrl+S: save • Esc: cancel"),
+		))
+	}
+
+	// Preview: title and section outline
+	title := article.Title()
+	if title == "" {
+		title = lipgloss.NewStyle().Foreground(statusError).Render("(no # title)")
+	}
+	var headings []string
+	for _, s := range article.Sections() {
+		headings = append(headings, s.Heading)
+	}
+	metaStyle := lipgloss.NewStyle().Foreground(textSecondary)
+	preview := lipgloss.NewStyle().Width(width - 8).Render(
+		lipgloss.NewStyle().Bold(true).Foreground(c1Primary).Render(title) + "\n" +
+			metaStyle.Render(fmt.Sprintf("%d sections: %s", len(headings), strings.Join(headings, " • "))))
+
+	divider := lipgloss.NewStyle().
+		Foreground(borderColor).
+		Render(strings.Repeat("─", width-8))
+
+	// Article body, scrolled with PgUp/PgDn
+	bodyHeight := height - 2 - 2 - lipgloss.Height(preview) - 3
+	if bodyHeight < 3 {
+		bodyHeight = 3
+	}
+	lines := strings.Split(renderKBMarkdown(article.Content, width-8), "\n")
+	scroll := m.kbScroll
+	if maxScroll := len(lines) - bodyHeight; scroll > maxScroll {
+		scroll = maxScroll
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	end := scroll + bodyHeight
+	if end > len(lines) {
+		end = len(lines)
+	}
+	body := lipgloss.NewStyle().Height(bodyHeight).Render(strings.Join(lines[scroll:end], "\n"))
+
+	footer := "[e/E] Edit • [c] Copy • [x] Export • [v] Select"
+	if len(lines) > bodyHeight {
+		footer = fmt.Sprintf("PgUp/PgDn: scroll (%d/%d) • ", end, len(lines)) + footer
+	}
+
+	return panel.Render(lipgloss.JoinVertical(
 		lipgloss.Left,
 		header,
-		contentView,
+		preview,
+		divider,
+		body,
 		dimmedTextStyle.Render(footer),
-	)
+	))
 }
 
-func (m model) renderKBView(width, height int) string {
-	// Placeholder for Phase 3E (V2)
-	return contentPanelStyle.
-		Width(width - 4).
-		Height(height - 2).
-		Render("KB Article view - V2 feature")
+// renderKBMarkdown styles headings and wraps the rest of the article. The #
+// title is left out; the preview above the body already shows it.
+func renderKBMarkdown(content string, width int) string {
+	headingStyle := lipgloss.NewStyle().Bold(true).Foreground(c1Primary)
+	subheadingStyle := lipgloss.NewStyle().Bold(true).Foreground(textPrimary)
+	textStyle := lipgloss.NewStyle().Foreground(textPrimary).Width(width)
+
+	var out []string
+	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		switch {
+		case strings.HasPrefix(line, "# "):
+			continue
+		case strings.HasPrefix(line, "## "):
+			out = append(out, headingStyle.Render(strings.TrimLeft(line, "# ")))
+		case strings.HasPrefix(line, "#"):
+			out = append(out, subheadingStyle.Render(strings.TrimLeft(line, "# ")))
+		default:
+			out = append(out, textStyle.Render(line))
+		}
+	}
+	return strings.Join(out, "\n")
 }
 
 func (m model) renderInfoBar(width int) string {
@@ -1050,22 +1215,18 @@ func (m model) renderNewReplyBanner(inv *Investigation, width int) string {
 	if maxLen < 20 {
 		maxLen = 20
 	}
-	if len(summary) > maxLen {
-		summary = summary[:maxLen-3] + "..."
-	}
+	summary = truncateStr(summary, maxLen)
 	if summary == "" {
 		summary = "New information available"
 	}
 
-	return bannerStyle.Render(fmt.Sprintf("📩 Customer replied — %s — Press Enter to review", summary))
+	return bannerStyle.Render(withIcon(glyphReply, fmt.Sprintf("Customer replied — %s — Press Enter to review", summary)))
 }
 
 func (m model) renderResetForm() string {
 	inv := m.getSelectedInvestigation()
-	dialogWidth := 65
-	dialogHeight := 16
-
-	title := titleStyle.Width(m.width).Render("Support Triage")
+	dialogWidth := m.modalWidth(65)
+	dialogHeight := m.modalHeight(16)
 
 	dialogStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -1079,7 +1240,7 @@ func (m model) renderResetForm() string {
 	dialogHeader := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(c1Primary).
-		Render("🔄 Reset Investigation")
+		Render(withIcon(glyphReset, "Reset Investigation"))
 
 	var invInfo string
 	if inv != nil {
@@ -1127,30 +1288,13 @@ func (m model) renderResetForm() string {
 		footer,
 	)
 
-	dialog := dialogStyle.Render(dialogContent)
-
-	centered := lipgloss.Place(
-		m.width,
-		m.height-2,
-		lipgloss.Center,
-		lipgloss.Center,
-		dialog,
-		lipgloss.WithWhitespaceChars(" "),
-	)
-
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		title,
-		centered,
-	)
+	return dialogStyle.Render(dialogContent)
 }
 
 func (m model) renderReplyPrompt() string {
 	inv := m.getSelectedInvestigation()
-	dialogWidth := 65
-	dialogHeight := 20
-
-	title := titleStyle.Width(m.width).Render("Support Triage")
+	dialogWidth := m.modalWidth(65)
+	dialogHeight := m.modalHeight(20)
 
 	dialogStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -1164,7 +1308,7 @@ func (m model) renderReplyPrompt() string {
 	dialogHeader := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("#F59E0B")).
-		Render("📩 Customer Reply Detected")
+		Render(withIcon(glyphReply, "Customer Reply Detected"))
 
 	var invInfo string
 	if inv != nil {
@@ -1219,22 +1363,7 @@ func (m model) renderReplyPrompt() string {
 		footer,
 	)
 
-	dialog := dialogStyle.Render(dialogContent)
-
-	centered := lipgloss.Place(
-		m.width,
-		m.height-2,
-		lipgloss.Center,
-		lipgloss.Center,
-		dialog,
-		lipgloss.WithWhitespaceChars(" "),
-	)
-
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		title,
-		centered,
-	)
+	return dialogStyle.Render(dialogContent)
 }
 
 func (m model) renderActionBar() string {
@@ -1253,20 +1382,42 @@ func (m model) renderActionBar() string {
 
 	// Show different hints based on active tab
 	var right string
-	debugHint := ""
+	extraHints := ""
 	if m.showDebugOverlay {
-		debugHint = " • ?: debug"
+		extraHints = " • ?: debug"
+	}
+	if len(m.errorLog) > 0 {
+		extraHints = fmt.Sprintf(" • !: errors (%d)", len(m.errorLog)) + extraHints
+	}
+	if m.paletteAvailable() {
+		extraHints = " • Ctrl+K: commands" + extraHints
 	}
-	if m.activeTab >= TabSlack && m.activeTab <= TabCodebase {
-		right = "↑↓: nav • 1-5: tabs • PgUp/PgDn: scroll • n: new • r: refresh • R: reset • a: approve • q: quit" + debugHint
+	if m.selecting {
+		right = "↑↓: move • Space: range • c: copy • m: markdown • L: permalink • Esc: done" + extraHints
+	} else if m.activeTab >= TabSlack && m.activeTab <= TabCodebase {
+		right = "↑↓: nav • 1-7: tabs • v: select • PgUp/PgDn: scroll • n: new • r: refresh • R: reset • a: approve • q: quit" + extraHints
 	} else if m.activeTab == TabSummary {
 		if m.editingResponse {
-			right = "Esc: cancel edit • 1-5: tabs • q: quit" + debugHint
+			right = "Esc: cancel edit • 1-7: tabs • q: quit" + extraHints
+		} else if m.history.open {
+			right = "↑↓: select version • Enter: restore • Esc: close history" + extraHints
 		} else {
-			right = "↑↓: nav • 1-5: tabs • e: edit • c: copy • p: post • n: new • R: reset • q: quit" + debugHint
+			right = "↑↓: nav • 1-7: tabs • v: select • e/E: edit • c: copy • p: post • h: history • n: new • R: reset • q: quit" + extraHints
+		}
+	} else if m.activeTab == TabKB {
+		if m.editingKB {
+			right = "Ctrl+S: save • Esc: cancel edit" + extraHints
+		} else {
+			right = "↑↓: nav • 1-7: tabs • v: select • e/E: edit • c: copy • x: export • g: generate • q: quit" + extraHints
+		}
+	} else if m.activeTab == TabLinearDraft {
+		if m.editingDraft {
+			right = "Tab: next field • Ctrl+S: save • Esc: done" + extraHints
+		} else {
+			right = "↑↓: nav • 1-7: tabs • v: select • e/E: edit • c: copy • p: file • n: new • q: quit" + extraHints
 		}
 	} else {
-		right = "↑↓: nav • 1-5: tabs • n: new • r: refresh • R: reset • a: approve • q: quit" + debugHint
+		right = "↑↓: nav • 1-7: tabs • n: new • r: refresh • R: reset • a: approve • q: quit" + extraHints
 	}
 
 	// Show reply count if any
@@ -1277,7 +1428,7 @@ func (m model) renderActionBar() string {
 		}
 	}
 	if newReplyCount > 0 {
-		left += fmt.Sprintf(" • 📩 %d reply", newReplyCount)
+		left += " • " + withIcon(glyphReply, fmt.Sprintf("%d reply", newReplyCount))
 	}
 
 	leftPart := actionBarStyle.Width(m.width/2 - 2).Render(left)
@@ -1287,12 +1438,16 @@ func (m model) renderActionBar() string {
 }
 
 func (m model) renderConfirmDialog() string {
-	// Create modal overlay
-	dialogWidth := 60
-	dialogHeight := 10
-
-	// Title bar (still show at top)
-	title := titleStyle.Width(m.width).Render("Support Triage")
+	dialogWidth := m.modalWidth(60)
+	dialogHeight := m.modalHeight(10)
+	if len(m.confirmDiff) > 0 {
+		dialogWidth = m.modalWidth(90)
+		// Room for the diff, up to a point
+		dialogHeight = m.modalHeight(12 + len(m.confirmDiff))
+		if dialogHeight > m.modalHeight(30) {
+			dialogHeight = m.modalHeight(30)
+		}
+	}
 
 	// Dialog box
 	dialogStyle := lipgloss.NewStyle().
@@ -1305,17 +1460,15 @@ func (m model) renderConfirmDialog() string {
 		Height(dialogHeight)
 
 	// Content
-	var icon string
-	if m.confirmAction == "post" {
-		icon = "📤"
-	} else if m.confirmAction == "save" {
-		icon = "💾"
+	header := "Confirmation"
+	if m.confirmAction == "save" {
+		header = withIcon(glyphSave, header)
 	}
 
 	dialogHeader := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(c1Primary).
-		Render(fmt.Sprintf("%s Confirmation", icon))
+		Render(header)
 
 	dialogMessage := lipgloss.NewStyle().
 		Foreground(textPrimary).
@@ -1327,37 +1480,20 @@ func (m model) renderConfirmDialog() string {
 		Padding(1, 0).
 		Render("[Y] Yes    [N] No")
 
-	dialogContent := lipgloss.JoinVertical(
-		lipgloss.Left,
-		dialogHeader,
-		dialogMessage,
-		dialogButtons,
-	)
-
-	dialog := dialogStyle.Render(dialogContent)
-
-	// Center the dialog
-	centered := lipgloss.Place(
-		m.width,
-		m.height-2, // Leave room for title
-		lipgloss.Center,
-		lipgloss.Center,
-		dialog,
-		lipgloss.WithWhitespaceChars(" "),
-	)
+	parts := []string{dialogHeader, dialogMessage}
+	if len(m.confirmDiff) > 0 {
+		// Header, message and buttons with their padding take 9 lines
+		parts = append(parts, renderDiff(m.confirmDiff, dialogWidth-8, dialogHeight-11))
+	}
+	parts = append(parts, dialogButtons)
+	dialogContent := lipgloss.JoinVertical(lipgloss.Left, parts...)
 
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		title,
-		centered,
-	)
+	return dialogStyle.Render(dialogContent)
 }
 
 func (m model) renderCreateForm() string {
-	dialogWidth := 70
-	dialogHeight := 22
-
-	title := titleStyle.Width(m.width).Render("Support Triage")
+	dialogWidth := m.modalWidth(70)
+	dialogHeight := m.modalHeight(22)
 
 	dialogStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -1436,22 +1572,7 @@ func (m model) renderCreateForm() string {
 		footer,
 	)
 
-	dialog := dialogStyle.Render(dialogContent)
-
-	centered := lipgloss.Place(
-		m.width,
-		m.height-2,
-		lipgloss.Center,
-		lipgloss.Center,
-		dialog,
-		lipgloss.WithWhitespaceChars(" "),
-	)
-
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		title,
-		centered,
-	)
+	return dialogStyle.Render(dialogContent)
 }
 
 func (m model) renderDebugOverlay(width, height int) string {
@@ -1469,6 +1590,11 @@ func (m model) renderDebugOverlay(width, height int) string {
 	// Section 2: Window & Layout
 	sections = append(sections, debugLabelStyle.Render("WINDOW & LAYOUT"))
 	sections = append(sections, debugRow("Terminal", fmt.Sprintf("%dx%d", m.width, m.height)))
+	glyphMode := "emoji"
+	if asciiMode {
+		glyphMode = "ascii"
+	}
+	sections = append(sections, debugRow("Glyphs", glyphMode))
 	sidebarWidth := m.width / 3
 	if sidebarWidth < 40 {
 		sidebarWidth = 40
@@ -1498,8 +1624,15 @@ func (m model) renderDebugOverlay(width, height int) string {
 	sections = append(sections, debugRow("loading", strconv.FormatBool(m.loading)))
 	sections = append(sections, debugRow("ready", strconv.FormatBool(m.ready)))
 	sections = append(sections, debugRow("editing", strconv.FormatBool(m.editingResponse)))
-	sections = append(sections, debugRow("confirm", strconv.FormatBool(m.showConfirmDialog)))
-	if m.showConfirmDialog {
+	modalNames := []string{}
+	for _, kind := range m.modals {
+		modalNames = append(modalNames, kind.String())
+	}
+	if len(modalNames) == 0 {
+		modalNames = append(modalNames, "none")
+	}
+	sections = append(sections, debugRow("modals", strings.Join(modalNames, " > ")))
+	if m.isModalOpen(modalConfirm) {
 		sections = append(sections, debugRow("action", m.confirmAction))
 	}
 	sections = append(sections, debugRow("debug", strconv.FormatBool(m.showDebugOverlay)))
@@ -1553,14 +1686,16 @@ func debugRow(label, value string) string {
 	return debugLabelStyle.Render(label+": ") + debugValueStyle.Render(value)
 }
 
+// truncateStr shortens s to at most maxLen terminal cells, measuring with
+// lipgloss so wide glyphs and multi-byte runes are never split.
 func truncateStr(s string, maxLen int) string {
 	if maxLen <= 3 {
 		return s
 	}
-	if len(s) > maxLen {
-		return s[:maxLen-3] + "..."
+	if lipgloss.Width(s) <= maxLen {
+		return s
 	}
-	return s
+	return runewidth.Truncate(s, maxLen, "...")
 }
 
 // formatCheckpointShort returns a compact string for sidebar meta: "CP3 Investigation ⏸ • Run #2"
@@ -1571,14 +1706,14 @@ func formatCheckpointShort(status, checkpoint string, runNumber int) string {
 	}
 
 	if status == "complete" {
-		return fmt.Sprintf("Complete ✅ • Run #%d", run)
+		return fmt.Sprintf("Complete %s • Run #%d", glyph(glyphDone), run)
 	}
 	if status == "error" {
 		cp := checkpointAbbrev(checkpoint)
 		if cp != "" {
-			return fmt.Sprintf("%s ✖ • Run #%d", cp, run)
+			return fmt.Sprintf("%s %s • Run #%d", cp, glyph(glyphFailed), run)
 		}
-		return fmt.Sprintf("Error ✖ • Run #%d", run)
+		return fmt.Sprintf("Error %s • Run #%d", glyph(glyphFailed), run)
 	}
 
 	cp := checkpointAbbrev(checkpoint)
@@ -1587,10 +1722,10 @@ func formatCheckpointShort(status, checkpoint string, runNumber int) string {
 	}
 
 	if status == "running" {
-		return fmt.Sprintf("%s 🔄 • Run #%d", cp, run)
+		return fmt.Sprintf("%s %s • Run #%d", cp, glyph(glyphActive), run)
 	}
 	if status == "waiting" {
-		return fmt.Sprintf("%s ⏸ • Run #%d", cp, run)
+		return fmt.Sprintf("%s %s • Run #%d", cp, glyph(glyphPaused), run)
 	}
 	return fmt.Sprintf("%s • Run #%d", cp, run)
 }
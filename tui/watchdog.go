@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// watchdogInterval is how often running investigations are checked for
+// stalled agents. Each check reads every running investigation's agents and
+// activity log, so it runs less often than the UI tick.
+const watchdogInterval = 15 * time.Second
+
+// agentHealth is the watchdog's view of one running agent
+type agentHealth struct {
+	Name         string
+	LastActivity time.Time // Last activity-log entry; zero if it never logged
+	Idle         time.Duration
+	Runtime      time.Duration
+	Stalled      bool // No log output for longer than the idle timeout
+	Overdue      bool // Running longer than the max duration
+}
+
+// investigationHealth summarises one running investigation. Agents is keyed
+// by lower-case agent name and only holds running agents.
+type investigationHealth struct {
+	InvestigationID int
+	Agents          map[string]agentHealth
+	LastActivity    time.Time
+	Idle            time.Duration
+	// Stalled is set when no agent is running (phase 0 and 2 run as a single
+	// process) and the activity log has gone quiet for the idle timeout.
+	Stalled     bool
+	IdleLimit   time.Duration
+	MaxDuration time.Duration
+}
+
+// flagged reports whether anything in the investigation needs attention
+func (h *investigationHealth) flagged() bool {
+	if h == nil {
+		return false
+	}
+	if h.Stalled {
+		return true
+	}
+	for _, a := range h.Agents {
+		if a.Stalled || a.Overdue {
+			return true
+		}
+	}
+	return false
+}
+
+// agent returns the health of a flagged agent, if any
+func (h *investigationHealth) agent(name string) (agentHealth, bool) {
+	if h == nil {
+		return agentHealth{}, false
+	}
+	a, ok := h.Agents[strings.ToLower(name)]
+	return a, ok && (a.Stalled || a.Overdue)
+}
+
+// problems describes each flagged agent, worst first
+func (h *investigationHealth) problems() []string {
+	var flagged []agentHealth
+	for _, a := range h.Agents {
+		if a.Stalled || a.Overdue {
+			flagged = append(flagged, a)
+		}
+	}
+	sort.Slice(flagged, func(i, j int) bool { return flagged[i].Idle > flagged[j].Idle })
+
+	var lines []string
+	for _, a := range flagged {
+		name := a.Name
+		if name != "" {
+			name = strings.ToUpper(name[:1]) + name[1:]
+		}
+		var parts []string
+		if a.Stalled {
+			parts = append(parts, fmt.Sprintf("no output for %s (limit %s)", formatDuration(a.Idle), formatDuration(h.IdleLimit)))
+		}
+		if a.Overdue {
+			parts = append(parts, fmt.Sprintf("running %s (max %s)", formatDuration(a.Runtime), formatDuration(h.MaxDuration)))
+		}
+		lines = append(lines, name+" agent: "+strings.Join(parts, ", "))
+	}
+	if h.Stalled {
+		lines = append(lines, fmt.Sprintf("No activity logged for %s (limit %s)", formatDuration(h.Idle), formatDuration(h.IdleLimit)))
+	}
+	return lines
+}
+
+// lastAgentActivity reads activity-log.jsonl and returns the newest entry
+// per phase1-<agent> tag (keyed by lower-case agent name) plus the newest
+// entry overall.
+func lastAgentActivity(investigationID int) (map[string]time.Time, time.Time) {
+	perAgent := map[string]time.Time{}
+	var latest time.Time
+
+	path := resolveInvestigationFile(investigationID, "activity-log.jsonl")
+	if path == "" {
+		return perAgent, latest
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return perAgent, latest
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			Timestamp string `json:"ts"`
+			Phase     string `json:"phase"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, entry.Timestamp)
+		if err != nil {
+			continue
+		}
+		if ts.After(latest) {
+			latest = ts
+		}
+		if agent := strings.TrimPrefix(entry.Phase, "phase1-"); agent != entry.Phase {
+			if ts.After(perAgent[agent]) {
+				perAgent[agent] = ts
+			}
+		}
+	}
+	return perAgent, latest
+}
+
+// checkInvestigationHealth compares each running agent's last log entry and
+// runtime against the configured limits.
+func checkInvestigationHealth(inv Investigation, agents map[string]*AgentState, idleLimit, maxDuration time.Duration, now time.Time) investigationHealth {
+	perAgent, latest := lastAgentActivity(inv.ID)
+	h := investigationHealth{
+		InvestigationID: inv.ID,
+		Agents:          map[string]agentHealth{},
+		LastActivity:    latest,
+		IdleLimit:       idleLimit,
+		MaxDuration:     maxDuration,
+	}
+
+	for name, state := range agents {
+		if state.Status != "running" {
+			continue
+		}
+		key := strings.ToLower(name)
+		a := agentHealth{Name: key, LastActivity: perAgent[key]}
+		// An agent that hasn't logged yet has been quiet since it started
+		since := a.LastActivity
+		if since.IsZero() || state.StartedAt.After(since) {
+			since = state.StartedAt
+		}
+		if !since.IsZero() {
+			a.Idle = now.Sub(since)
+			a.Stalled = a.Idle > idleLimit
+		}
+		if !state.StartedAt.IsZero() {
+			a.Runtime = now.Sub(state.StartedAt)
+			a.Overdue = a.Runtime > maxDuration
+		}
+		h.Agents[key] = a
+	}
+
+	if len(h.Agents) == 0 {
+		since := latest
+		if since.IsZero() {
+			since, _ = parseServerTime(inv.UpdatedAt)
+		}
+		if !since.IsZero() {
+			h.Idle = now.Sub(since)
+			h.Stalled = h.Idle > idleLimit
+		}
+	}
+	return h
+}
+
+// Check every running investigation for stalled or overdue agents.
+// Investigations whose agents can't be loaded are left out rather than
+// reported: the watchdog shouldn't add toasts on top of the load errors.
+func watchdogCmd(invs []Investigation, idleLimit, maxDuration time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		reports := map[int]*investigationHealth{}
+		for _, inv := range invs {
+			if inv.Status != "running" {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			agents, err := fetchAgentStates(ctx, inv.ID)
+			cancel()
+			if err != nil {
+				continue
+			}
+			h := checkInvestigationHealth(inv, agents, idleLimit, maxDuration, now)
+			reports[inv.ID] = &h
+		}
+		return watchdogCheckedMsg{reports: reports, checkedAt: now}
+	}
+}
+
+// watchdogDue reports whether the next tick should run the watchdog
+func (m model) watchdogDue(now time.Time) bool {
+	if now.Sub(m.watchdogCheckedAt) < watchdogInterval {
+		return false
+	}
+	for _, inv := range m.investigations {
+		if inv.Status == "running" {
+			return true
+		}
+	}
+	return false
+}
+
+// Re-run the investigation's current phase on the server
+func retryInvestigationCmd(investigationID int) tea.Cmd {
+	return func() tea.Msg {
+		path := fmt.Sprintf("/api/investigations/%d/retry", investigationID)
+		if err := api.post(context.Background(), path, nil, nil); err != nil {
+			return errMsg{err: err, source: "retry investigation", retry: retryInvestigationCmd(investigationID)}
+		}
+		return investigationRetriedMsg{investigationID: investigationID}
+	}
+}
+
+// confirmStalledRetry asks before retrying a flagged investigation
+func (m *model) confirmStalledRetry(inv *Investigation) tea.Cmd {
+	h := m.watchdog[inv.ID]
+	if !h.flagged() {
+		return m.notify(severityInfo, "retry investigation", fmt.Sprintf("#%d isn't stalled; nothing to retry", inv.ID), nil)
+	}
+	m.pushModal(modalConfirm)
+	m.confirmAction = "retry_stalled"
+	m.confirmMessage = fmt.Sprintf("#%d looks stuck:\n%s\n\nRetry the current phase?", inv.ID, strings.Join(h.problems(), "\n"))
+	return nil
+}
+
+// renderWatchdogBanner warns about a stalled or overdue investigation
+func (m model) renderWatchdogBanner(inv *Investigation, width int) string {
+	h := m.watchdog[inv.ID]
+	if inv.Status != "running" || !h.flagged() {
+		return ""
+	}
+	innerWidth := width - 8
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Background(statusError).
+		Padding(0, 1).
+		Width(innerWidth)
+	detailStyle := lipgloss.NewStyle().Foreground(textSecondary).Width(innerWidth)
+	hintStyle := lipgloss.NewStyle().Foreground(textMuted).Width(innerWidth)
+
+	lines := []string{headerStyle.Render(withIcon(glyphStalled, "Investigation may be stalled")), ""}
+	for _, p := range h.problems() {
+		lines = append(lines, detailStyle.Render(p))
+	}
+	lines = append(lines, "",
+		hintStyle.Render("[ctrl+r] retry current phase  [R] reset"),
+		lipgloss.NewStyle().Foreground(borderColor).Render(strings.Repeat("─", innerWidth)),
+	)
+	return contentPanelStyle.
+		Width(width - 4).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
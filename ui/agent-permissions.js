@@ -62,6 +62,22 @@ export const AGENT_REQUIRED_TOOLS = {
     mcpServers: [],
     tools: ['Read', 'Glob'],
   },
+  // Outward actions the TUI asks for through the API
+  respond: {
+    mcpServers: ['pylon'],
+    tools: [
+      'mcp__pylon__pylon_get_issue',
+      'mcp__pylon__pylon_create_issue_message',
+    ],
+  },
+  linear_issue: {
+    mcpServers: ['linear'],
+    tools: [
+      'mcp__linear-server__list_teams',
+      'mcp__linear-server__list_issue_labels',
+      'mcp__linear-server__create_issue',
+    ],
+  },
 }
 
 /**
@@ -167,7 +167,7 @@ export function inferProductArea(classification, bodyText, connectorName) {
  */
 function runClaude(prompt, cwd, opts = {}) {
   return new Promise((resolve, reject) => {
-    const { investigationDir, phase, allowedTools } = opts
+    const { investigationDir, phase, allowedTools, onSpawn } = opts
     let child
     const timeoutMs = 300000 // 5 minutes — MCP tool calls can take a while
     const timeout = setTimeout(() => {
@@ -197,6 +197,7 @@ function runClaude(prompt, cwd, opts = {}) {
       reject(new Error(`Failed to spawn claude: ${err.message}`))
       return
     }
+    if (onSpawn) onSpawn(child)
 
     let stdout = ''
     let stderr = ''
@@ -607,22 +608,37 @@ Write structured markdown findings:
  * @param {object} dbHelpers
  * @param {number} runNumber
  */
+const PHASE1_AGENTS = ['pylon', 'slack', 'linear', 'codebase']
+
+// Agent processes by "ticketId:agentName", so a single agent can be stopped
+const runningAgents = new Map()
+
 async function runSingleAgent(agentName, prompt, ticketId, investigationDir, dbHelpers, runNumber) {
   const agentStartTime = Date.now()
   const findingsFile = `${agentName}-findings.md`
+  const agentKey = `${ticketId}:${agentName}`
+  const handle = { child: null, stopped: false }
 
   try {
     // Mark running
     dbHelpers.upsertAgent(ticketId, runNumber, agentName, {
       status: 'running',
-      started_at: new Date().toISOString()
+      started_at: new Date().toISOString(),
+      completed_at: null,
+      error_message: null,
+      pid: null
     })
     writeActivity(investigationDir, `phase1-${agentName}`, 'start', `${agentName} agent starting`)
 
+    runningAgents.set(agentKey, handle)
     const output = await runClaude(prompt, investigationDir, {
       investigationDir,
       phase: `phase1-${agentName}`,
-      allowedTools: getAllowedToolsForAgent(agentName)
+      allowedTools: getAllowedToolsForAgent(agentName),
+      onSpawn: (child) => {
+        handle.child = child
+        dbHelpers.upsertAgent(ticketId, runNumber, agentName, { pid: child.pid })
+      }
     })
 
     // Write per-agent findings
@@ -650,36 +666,72 @@ async function runSingleAgent(agentName, prompt, ticketId, investigationDir, dbH
     return { agent: agentName, status: 'completed', output }
   } catch (err) {
     const elapsed = ((Date.now() - agentStartTime) / 1000).toFixed(1)
-    writeActivity(investigationDir, `phase1-${agentName}`, 'error', `${agentName} agent failed (${elapsed}s): ${err.message}`)
+    const message = handle.stopped ? 'Stopped by user' : err.message
+    writeActivity(investigationDir, `phase1-${agentName}`, 'error', `${agentName} agent failed (${elapsed}s): ${message}`)
 
     dbHelpers.upsertAgent(ticketId, runNumber, agentName, {
       status: 'error',
       completed_at: new Date().toISOString(),
-      error_message: err.message
+      error_message: message
     })
 
-    return { agent: agentName, status: 'error', error: err.message }
+    return { agent: agentName, status: 'error', error: message }
+  } finally {
+    if (runningAgents.get(agentKey) === handle) runningAgents.delete(agentKey)
   }
 }
 
 /**
- * Phase 1: Multi-Agent Context Gathering
- * Spawns 4 parallel agents: pylon, slack, linear, codebase
+ * Stop a running Phase 1 agent. The agent is recorded as failed with
+ * "Stopped by user" and can be retried. Returns false if it isn't running.
  */
-export async function runPhase1MultiAgent(ticketId, investigationDir, dbHelpers, runNumber = 1) {
-  const startTime = Date.now()
-  console.log(`[Phase1-MultiAgent] Starting for ticket #${ticketId}`)
-  writeActivity(investigationDir, 'phase1', 'start', `Starting Phase 1 (multi-agent) for ticket #${ticketId}`)
+export function stopAgent(ticketId, agentName) {
+  const handle = runningAgents.get(`${ticketId}:${agentName}`)
+  if (!handle || !handle.child) return false
+  handle.stopped = true
+  handle.child.kill('SIGTERM')
+  return true
+}
 
-  try {
-    const tdPath = join(investigationDir, 'ticket-data.json')
-    if (!existsSync(tdPath)) throw new Error('ticket-data.json not found')
-    const td = JSON.parse(readFileSync(tdPath, 'utf-8'))
+/**
+ * Re-run one Phase 1 agent, with the reviewer's extra context appended to its
+ * prompt, then rebuild phase1-findings.md from every agent's latest result.
+ */
+export async function retryAgent(agentName, ticketId, investigationDir, dbHelpers, runNumber, extraContext = '') {
+  const td = JSON.parse(readFileSync(join(investigationDir, 'ticket-data.json'), 'utf-8'))
+  let prompt = buildAgentPrompts(ticketId, td)[agentName]
+  if (!prompt) throw new Error(`Unknown agent: ${agentName}`)
+  if (extraContext) {
+    prompt += `\n\nADDITIONAL CONTEXT FROM REVIEWER:\n${extraContext}`
+  }
+  writeActivity(investigationDir, 'phase1', 'info', `Retrying ${agentName} agent${extraContext ? ' with reviewer context' : ''}`)
 
-    writeActivity(investigationDir, 'phase1', 'info', `Loaded ticket: "${td.title}" (${td.classification})`)
+  const result = await runSingleAgent(agentName, prompt, ticketId, investigationDir, dbHelpers, runNumber)
+
+  // Rebuild the merged findings from what each agent has now
+  const results = PHASE1_AGENTS.map(name => {
+    const row = dbHelpers.queryOne(
+      'SELECT status, error_message, findings_file FROM agents WHERE investigation_id = ? AND run_number = ? AND agent_name = ?',
+      [ticketId, runNumber, name]
+    )
+    const findingsPath = join(investigationDir, row?.findings_file || `${name}-findings.md`)
+    if (row?.status === 'completed' && existsSync(findingsPath)) {
+      return { agent: name, status: 'completed', output: readFileSync(findingsPath, 'utf-8') }
+    }
+    return { agent: name, status: 'error', error: row?.error_message || 'Agent did not run' }
+  })
+  const { completedAgents, failedAgents } = mergeAgentFindings(ticketId, investigationDir, results)
+  writeActivity(investigationDir, 'phase1', 'result', `Re-merged findings after retrying ${agentName} (${completedAgents.length} agents, ${failedAgents.length} failed)`)
 
-    // Build shared context header
-    const ticketContext = `TICKET #${ticketId}
+  return result
+}
+
+/**
+ * Per-agent Phase 1 prompts for a ticket, keyed by agent name
+ */
+function buildAgentPrompts(ticketId, td) {
+  // Build shared context header
+  const ticketContext = `TICKET #${ticketId}
 Customer: ${td.customer_name}
 Title: ${td.title}
 Classification: ${td.classification}
@@ -687,9 +739,8 @@ Product Area: ${td.product_area}
 ${td.connector_name ? `Connector: ${td.connector_name}` : ''}
 Body: ${td.body || '(no body)'}`
 
-    // Define per-agent prompts
-    const agentPrompts = {
-      pylon: `You are a Pylon research agent for ConductorOne support. Your ONLY job is to search Pylon for related issues.
+  return {
+    pylon: `You are a Pylon research agent for ConductorOne support. Your ONLY job is to search Pylon for related issues.
 
 ${ticketContext}
 
@@ -709,7 +760,7 @@ OUTPUT FORMAT (markdown):
 ## Gaps / Unknowns
 (What couldn't be searched)`,
 
-      slack: `You are a Slack research agent for ConductorOne support. Your ONLY job is to search Slack for discussions related to this ticket.
+    slack: `You are a Slack research agent for ConductorOne support. Your ONLY job is to search Slack for discussions related to this ticket.
 
 ${ticketContext}
 
@@ -730,7 +781,7 @@ OUTPUT FORMAT (markdown):
 ## Gaps / Unknowns
 (What couldn't be searched)`,
 
-      linear: `You are a Linear research agent for ConductorOne support. Your ONLY job is to search Linear for related engineering issues.
+    linear: `You are a Linear research agent for ConductorOne support. Your ONLY job is to search Linear for related engineering issues.
 
 ${ticketContext}
 
@@ -751,7 +802,7 @@ OUTPUT FORMAT (markdown):
 ## Gaps / Unknowns
 (What couldn't be searched)`,
 
-      codebase: `You are a codebase research agent for ConductorOne support. Your ONLY job is to find relevant code for this ticket.
+    codebase: `You are a codebase research agent for ConductorOne support. Your ONLY job is to find relevant code for this ticket.
 
 ${ticketContext}
 
@@ -776,10 +827,56 @@ OUTPUT FORMAT (markdown):
 
 ## Gaps / Unknowns
 (What couldn't be found)`
+  }
+}
+
+/**
+ * Write phase1-findings.md from each agent's result, in the order given
+ */
+function mergeAgentFindings(ticketId, investigationDir, agentResults) {
+  let merged = `# Phase 1 Findings — Ticket #${ticketId}\n\n`
+  merged += `_Generated by multi-agent system at ${new Date().toISOString()}_\n\n`
+
+  const completedAgents = []
+  const failedAgents = []
+
+  for (const agentResult of agentResults) {
+    const title = agentResult.agent.charAt(0).toUpperCase() + agentResult.agent.slice(1)
+    if (agentResult.status === 'completed') {
+      completedAgents.push(agentResult.agent)
+      merged += `---\n## ${title} Agent Findings\n\n`
+      merged += agentResult.output + '\n\n'
+    } else {
+      failedAgents.push(agentResult.agent)
+      merged += `---\n## ${title} Agent — Error\n\n`
+      merged += `_Agent failed: ${agentResult.error}_\n\n`
     }
+  }
+
+  writeFileSync(join(investigationDir, 'phase1-findings.md'), merged)
+  return { merged, completedAgents, failedAgents }
+}
+
+/**
+ * Phase 1: Multi-Agent Context Gathering
+ * Spawns 4 parallel agents: pylon, slack, linear, codebase
+ */
+export async function runPhase1MultiAgent(ticketId, investigationDir, dbHelpers, runNumber = 1) {
+  const startTime = Date.now()
+  console.log(`[Phase1-MultiAgent] Starting for ticket #${ticketId}`)
+  writeActivity(investigationDir, 'phase1', 'start', `Starting Phase 1 (multi-agent) for ticket #${ticketId}`)
+
+  try {
+    const tdPath = join(investigationDir, 'ticket-data.json')
+    if (!existsSync(tdPath)) throw new Error('ticket-data.json not found')
+    const td = JSON.parse(readFileSync(tdPath, 'utf-8'))
+
+    writeActivity(investigationDir, 'phase1', 'info', `Loaded ticket: "${td.title}" (${td.classification})`)
+
+    const agentPrompts = buildAgentPrompts(ticketId, td)
 
     // Insert all agents as pending
-    const agentNames = ['pylon', 'slack', 'linear', 'codebase']
+    const agentNames = PHASE1_AGENTS
     for (const name of agentNames) {
       dbHelpers.upsertAgent(ticketId, runNumber, name, { status: 'pending' })
     }
@@ -793,26 +890,10 @@ OUTPUT FORMAT (markdown):
     const results = await Promise.allSettled(agentPromises)
 
     // Merge all findings into phase1-findings.md for backward compat
-    let merged = `# Phase 1 Findings — Ticket #${ticketId}\n\n`
-    merged += `_Generated by multi-agent system at ${new Date().toISOString()}_\n\n`
-
-    const completedAgents = []
-    const failedAgents = []
-
-    for (const result of results) {
-      const agentResult = result.status === 'fulfilled' ? result.value : { agent: 'unknown', status: 'error', error: result.reason?.message }
-      if (agentResult.status === 'completed') {
-        completedAgents.push(agentResult.agent)
-        merged += `---\n## ${agentResult.agent.charAt(0).toUpperCase() + agentResult.agent.slice(1)} Agent Findings\n\n`
-        merged += agentResult.output + '\n\n'
-      } else {
-        failedAgents.push(agentResult.agent)
-        merged += `---\n## ${agentResult.agent.charAt(0).toUpperCase() + agentResult.agent.slice(1)} Agent — Error\n\n`
-        merged += `_Agent failed: ${agentResult.error}_\n\n`
-      }
-    }
-
-    writeFileSync(join(investigationDir, 'phase1-findings.md'), merged)
+    const agentResults = results.map(result =>
+      result.status === 'fulfilled' ? result.value : { agent: 'unknown', status: 'error', error: result.reason?.message }
+    )
+    const { merged, completedAgents, failedAgents } = mergeAgentFindings(ticketId, investigationDir, agentResults)
     writeActivity(investigationDir, 'phase1', 'result', `Merged findings from ${completedAgents.length} agents (${failedAgents.length} failed)`)
 
     // Parse source citations from merged findings
@@ -1028,6 +1109,138 @@ Write complete documents.`
   }
 }
 
+/**
+ * Post a customer response on the ticket's Pylon thread, exactly as written.
+ * Resolves with { message_id, posted_at } once Pylon has accepted it; throws
+ * when the post can't be confirmed.
+ */
+export async function postCustomerResponse(ticketId, investigationDir, content) {
+  writeActivity(investigationDir, 'respond', 'start', `Posting customer response to Pylon #${ticketId} (${content.length} chars)`)
+
+  const prompt = `You are a posting tool. Using the Pylon MCP tools, post the message between the markers below as a reply on Pylon issue "${ticketId}".
+Post it exactly once and exactly as written: do not edit, summarize or add to it.
+
+Return ONLY a raw JSON object (no markdown, no code blocks):
+{"message_id":"<id of the posted message>","posted_at":"<ISO 8601 time it was posted>"}
+If the post fails, return {"error":"<reason>"} instead.
+
+<<<MESSAGE
+${content}
+MESSAGE>>>`
+
+  const output = await runClaude(prompt, TRIAGE_DIR, {
+    investigationDir,
+    phase: 'respond',
+    allowedTools: getAllowedToolsForAgent('respond')
+  })
+  const result = parseJSONOutput(output)
+  if (!result.message_id) {
+    const reason = result.error || 'Pylon returned no message ID'
+    writeActivity(investigationDir, 'respond', 'error', `Post failed: ${reason}`)
+    throw new Error(reason)
+  }
+  writeActivity(investigationDir, 'respond', 'complete', `Posted as Pylon message ${result.message_id}`)
+  return { message_id: String(result.message_id), posted_at: result.posted_at || new Date().toISOString() }
+}
+
+/**
+ * File a reviewed Linear draft as a new issue, exactly as written.
+ * Resolves with { identifier, url } once Linear has created it; throws when
+ * the issue can't be confirmed.
+ */
+export async function fileLinearIssue(ticketId, investigationDir, draft) {
+  writeActivity(investigationDir, 'linear-issue', 'start', `Filing Linear issue for #${ticketId} with team ${draft.team}`)
+
+  const labels = draft.labels.length > 0 ? draft.labels.join(', ') : '(none)'
+  const prompt = `You are a Linear issue filing tool. Using the Linear MCP tools, create exactly one issue from the draft below.
+Look up the team by name and use the labels that exist on it; skip any label that doesn't exist.
+Use the title and description exactly as written: do not edit, summarize or add to them.
+
+Team: ${draft.team}
+Priority: ${draft.priority || 'No priority'}
+Labels: ${labels}
+Title: ${draft.title}
+
+Return ONLY a raw JSON object (no markdown, no code blocks):
+{"identifier":"<issue identifier, e.g. ENG-123>","url":"<issue URL>"}
+If filing fails, return {"error":"<reason>"} instead.
+
+<<<DESCRIPTION
+${draft.description}
+DESCRIPTION>>>`
+
+  const output = await runClaude(prompt, TRIAGE_DIR, {
+    investigationDir,
+    phase: 'linear-issue',
+    allowedTools: getAllowedToolsForAgent('linear_issue')
+  })
+  const result = parseJSONOutput(output)
+  if (!result.identifier) {
+    const reason = result.error || 'Linear returned no issue identifier'
+    writeActivity(investigationDir, 'linear-issue', 'error', `Filing failed: ${reason}`)
+    throw new Error(reason)
+  }
+  writeActivity(investigationDir, 'linear-issue', 'complete', `Filed as ${result.identifier}`)
+  return { identifier: String(result.identifier), url: result.url || '' }
+}
+
+/**
+ * Draft a knowledge base article from a completed investigation's documents
+ * and write it to kb-article.md. Resolves with the article's path.
+ */
+export async function generateKBArticle(ticketId, investigationDir) {
+  writeActivity(investigationDir, 'kb-article', 'start', `Drafting KB article for #${ticketId}`)
+
+  const docs = ['summary.md', 'customer-response.md', 'phase1-findings.md']
+    .filter(name => existsSync(join(investigationDir, name)))
+    .map(name => `=== ${name} ===\n${readFileSync(join(investigationDir, name), 'utf-8')}`)
+  if (docs.length === 0) {
+    writeActivity(investigationDir, 'kb-article', 'error', 'No investigation documents to draft from')
+    throw new Error(`#${ticketId} has no summary, response or findings to draft from`)
+  }
+
+  const prompt = `Write a knowledge base article from the resolved ConductorOne support investigation #${ticketId} below.
+The audience is other customers and support engineers. Leave out customer names, ticket numbers and anything internal.
+
+Use this structure, in markdown:
+# <Short, searchable title>
+## Problem
+## Cause
+## Resolution
+## Related
+
+Return ONLY the article markdown, starting with the title line.
+
+${docs.join('\n\n')}`
+
+  const output = await runClaude(prompt, TRIAGE_DIR, {
+    investigationDir,
+    phase: 'kb-article'
+  })
+  const article = output.trim()
+  if (!article.startsWith('#')) {
+    writeActivity(investigationDir, 'kb-article', 'error', 'Agent returned no article')
+    throw new Error(`Agent returned no article: ${article.slice(0, 200)}`)
+  }
+  const path = join(investigationDir, 'kb-article.md')
+  writeFileSync(path, article + '\n')
+  writeActivity(investigationDir, 'kb-article', 'complete', `Wrote kb-article.md (${article.length} chars)`)
+  return path
+}
+
+/**
+ * Pull the JSON object out of an agent's reply
+ */
+function parseJSONOutput(output) {
+  const jsonMatch = output.match(/\{[\s\S]*\}/)
+  if (!jsonMatch) throw new Error(`Agent returned no JSON: ${output.slice(0, 200)}`)
+  try {
+    return JSON.parse(jsonMatch[0])
+  } catch (err) {
+    throw new Error(`Agent returned invalid JSON: ${err.message}`)
+  }
+}
+
 function parseDelimitedOutput(output) {
   const sections = {}
   const parts = output.split(/===\s*([\w.-]+)\s*===/)
@@ -7,7 +7,7 @@ import { readFile } from 'fs/promises'
 import { join, resolve } from 'path'
 import { fileURLToPath } from 'url'
 import { dirname } from 'path'
-import { runPhase0, runPhase1, runPhase1MultiAgent, runPhase2, populateFromTicketData } from './investigation-runner.js'
+import { runPhase0, runPhase1, runPhase1MultiAgent, runPhase2, stopAgent, retryAgent, populateFromTicketData, postCustomerResponse, fileLinearIssue, generateKBArticle } from './investigation-runner.js'
 import { checkPermissions, fixAllPermissions, getAllowedToolsForAgent, AGENT_REQUIRED_TOOLS } from './agent-permissions.js'
 import { createSnapshot, restoreToVersion, getVersions, getVersionDiff } from './version-manager.js'
 import { syncTicketResponses, checkForNewResponses } from './response-sync.js'
@@ -719,6 +719,112 @@ app.post('/api/investigations/:id/sync-responses', (req, res) => {
   }
 })
 
+// POST /api/investigations/:id/responses/post — post the customer response to Pylon
+// Waits for the post to land so the caller gets the Pylon message ID back.
+const postingResponses = new Set()
+app.post('/api/investigations/:id/responses/post', async (req, res) => {
+  const id = parseInt(req.params.id)
+  const content = typeof req.body?.content === 'string' ? req.body.content.trim() : ''
+  try {
+    const inv = queryOne('SELECT id FROM investigations WHERE id = ?', [id])
+    if (!inv) return res.status(404).json({ error: 'Investigation not found' })
+  } catch (error) {
+    return res.status(500).json({ error: error.message })
+  }
+  if (!content) return res.status(400).json({ error: 'content is required' })
+  if (postingResponses.has(id)) {
+    return res.status(409).json({ error: `A response for #${id} is already being posted` })
+  }
+
+  postingResponses.add(id)
+  try {
+    const investigationDir = join(INVESTIGATIONS_DIR, String(id))
+    const result = await postCustomerResponse(id, investigationDir, content)
+    res.json({ messageId: result.message_id, postedAt: result.posted_at })
+  } catch (error) {
+    console.error(`Error posting response for #${id}:`, error.message)
+    res.status(502).json({ error: error.message })
+  } finally {
+    postingResponses.delete(id)
+  }
+})
+
+// POST /api/investigations/:id/linear-issue — file the reviewed Linear draft
+// Waits for Linear to create the issue and records it so it isn't filed twice.
+const filingLinearIssues = new Set()
+app.post('/api/investigations/:id/linear-issue', async (req, res) => {
+  const id = parseInt(req.params.id)
+  const body = req.body || {}
+  const text = (v) => (typeof v === 'string' ? v.trim() : '')
+  const draft = {
+    title: text(body.title),
+    team: text(body.team),
+    priority: text(body.priority),
+    labels: Array.isArray(body.labels) ? body.labels.map(text).filter(Boolean) : [],
+    description: typeof body.description === 'string' ? body.description : ''
+  }
+  try {
+    const inv = queryOne('SELECT id, linear_issue_id FROM investigations WHERE id = ?', [id])
+    if (!inv) return res.status(404).json({ error: 'Investigation not found' })
+    if (inv.linear_issue_id) {
+      return res.status(409).json({ error: `#${id} is already filed as ${inv.linear_issue_id}` })
+    }
+  } catch (error) {
+    return res.status(500).json({ error: error.message })
+  }
+  if (!draft.title || !draft.team) return res.status(400).json({ error: 'title and team are required' })
+  if (filingLinearIssues.has(id)) {
+    return res.status(409).json({ error: `A Linear issue for #${id} is already being filed` })
+  }
+
+  filingLinearIssues.add(id)
+  try {
+    const investigationDir = join(INVESTIGATIONS_DIR, String(id))
+    const result = await fileLinearIssue(id, investigationDir, draft)
+    dbHelpers.updateInvestigation(id, { linear_issue_id: result.identifier })
+    res.json(result)
+  } catch (error) {
+    console.error(`Error filing Linear issue for #${id}:`, error.message)
+    res.status(502).json({ error: error.message })
+  } finally {
+    filingLinearIssues.delete(id)
+  }
+})
+
+// POST /api/investigations/:id/kb-article — draft a KB article for a completed investigation
+// Waits for kb-article.md to be written so failures reach the caller.
+const generatingKBArticles = new Set()
+app.post('/api/investigations/:id/kb-article', async (req, res) => {
+  const id = parseInt(req.params.id)
+  const investigationDir = join(INVESTIGATIONS_DIR, String(id))
+  try {
+    const inv = queryOne('SELECT id, status FROM investigations WHERE id = ?', [id])
+    if (!inv) return res.status(404).json({ error: 'Investigation not found' })
+    if (inv.status !== 'complete') {
+      return res.status(409).json({ error: 'KB articles can only be drafted for completed investigations' })
+    }
+  } catch (error) {
+    return res.status(500).json({ error: error.message })
+  }
+  if (existsSync(join(investigationDir, 'kb-article.md'))) {
+    return res.status(409).json({ error: `#${id} already has a kb-article.md` })
+  }
+  if (generatingKBArticles.has(id)) {
+    return res.status(409).json({ error: `A KB article for #${id} is already being drafted` })
+  }
+
+  generatingKBArticles.add(id)
+  try {
+    await generateKBArticle(id, investigationDir)
+    res.json({ file: 'kb-article.md' })
+  } catch (error) {
+    console.error(`Error drafting KB article for #${id}:`, error.message)
+    res.status(502).json({ error: error.message })
+  } finally {
+    generatingKBArticles.delete(id)
+  }
+})
+
 // GET /api/investigations/:id/agents — list agents for current run
 app.get('/api/investigations/:id/agents', (req, res) => {
   try {
@@ -734,6 +840,73 @@ app.get('/api/investigations/:id/agents', (req, res) => {
   }
 })
 
+// POST /api/investigations/:id/agents/:name/stop — stop one running Phase 1 agent
+app.post('/api/investigations/:id/agents/:name/stop', (req, res) => {
+  const id = parseInt(req.params.id)
+  const name = req.params.name.toLowerCase()
+  try {
+    const inv = queryOne('SELECT id FROM investigations WHERE id = ?', [id])
+    if (!inv) return res.status(404).json({ error: 'Investigation not found' })
+    if (!stopAgent(id, name)) {
+      return res.status(409).json({ error: `The ${name} agent isn't running on #${id}` })
+    }
+    res.json({ stopped: true })
+  } catch (error) {
+    console.error(`Error stopping ${name} agent for #${id}:`, error.message)
+    res.status(500).json({ error: error.message })
+  }
+})
+
+// POST /api/investigations/:id/agents/:name/retry — re-run one Phase 1 agent
+// Body: { context } — extra context from the reviewer, appended to the prompt.
+// The investigation shows as running until its last retry finishes, then goes
+// back to the status and checkpoint it had.
+const agentRetries = new Map() // id -> { count, status, checkpoint }
+app.post('/api/investigations/:id/agents/:name/retry', (req, res) => {
+  const id = parseInt(req.params.id)
+  const name = req.params.name.toLowerCase()
+  const extraContext = typeof req.body?.context === 'string' ? req.body.context.trim() : ''
+  let inv
+  try {
+    inv = queryOne('SELECT id, status, current_checkpoint, current_run_number FROM investigations WHERE id = ?', [id])
+    if (!inv) return res.status(404).json({ error: 'Investigation not found' })
+  } catch (error) {
+    return res.status(500).json({ error: error.message })
+  }
+  if (!['pylon', 'slack', 'linear', 'codebase'].includes(name)) {
+    return res.status(400).json({ error: `Unknown agent: ${name}` })
+  }
+  const runNum = inv.current_run_number || 1
+  const agent = queryOne(
+    'SELECT status FROM agents WHERE investigation_id = ? AND run_number = ? AND agent_name = ?',
+    [id, runNum, name]
+  )
+  if (!agent) return res.status(404).json({ error: `The ${name} agent hasn't run on #${id}` })
+  if (agent.status === 'running' || agent.status === 'pending') {
+    return res.status(409).json({ error: `The ${name} agent is still running; stop it first` })
+  }
+  if (inv.status === 'running' && !agentRetries.has(id)) {
+    return res.status(409).json({ error: `#${id} is still running; retry once it stops` })
+  }
+
+  const retries = agentRetries.get(id) || { count: 0, status: inv.status, checkpoint: inv.current_checkpoint }
+  retries.count++
+  agentRetries.set(id, retries)
+  dbHelpers.updateInvestigation(id, { status: 'running' })
+
+  const investigationDir = join(INVESTIGATIONS_DIR, String(id))
+  retryAgent(name, id, investigationDir, dbHelpers, runNum, extraContext)
+    .catch(err => console.error(`Error retrying ${name} agent for #${id}:`, err.message))
+    .finally(() => {
+      if (--retries.count > 0) return
+      agentRetries.delete(id)
+      const ts = new Date().toISOString().replace('T', ' ').split('.')[0]
+      dbHelpers.updateInvestigation(id, { status: retries.status, current_checkpoint: retries.checkpoint, updated_at: ts })
+    })
+
+  res.status(202).json({ status: 'started' })
+})
+
 // GET /api/investigations/:id/runs — list all runs for this ticket
 app.get('/api/investigations/:id/runs', (req, res) => {
   try {